@@ -0,0 +1,175 @@
+package fuzzy
+
+import (
+	"golang.org/x/text/transform"
+)
+
+// RankMatchAligned is similar to RankMatch, but instead of counting the
+// unmatched runes, it finds the most compact placement of source in target
+// and returns its cost: the number of unmatched runes between the first and
+// the last matched rune, plus the number of gaps between runs of consecutive
+// matched runes. If there was no match, it will return -1.
+//
+// Unlike Match, which picks the leftmost occurrence of each rune, this finds
+// "ab" at the end of "a...xab" with a cost of 0.
+func RankMatchAligned(source, target string) int {
+	return rankAligned(source, target, noopTransformer())
+}
+
+// RankMatchAlignedFold is a case-insensitive version of RankMatchAligned.
+func RankMatchAlignedFold(source, target string) int {
+	return rankAligned(source, target, foldTransformer())
+}
+
+func rankAligned(source, target string, transformer transform.Transformer) int {
+	sourceT := stringTransform(source, transformer)
+	targetT := stringTransform(target, transformer)
+	cost, _ := alignCompact([]rune(sourceT), []rune(targetT))
+	return cost
+}
+
+// RankFindAligned is similar to RankFind, except the Distance of each match
+// is the cost of the most compact placement, see RankMatchAligned.
+func RankFindAligned(source string, targets []string) Ranks {
+	return rankFindAligned(source, targets, noopTransformer())
+}
+
+// RankFindAlignedFold is a case-insensitive version of RankFindAligned.
+func RankFindAlignedFold(source string, targets []string) Ranks {
+	return rankFindAligned(source, targets, foldTransformer())
+}
+
+func rankFindAligned(source string, targets []string, transformer transform.Transformer) Ranks {
+	sourceR := []rune(stringTransform(source, transformer))
+
+	var r Ranks
+
+	for index, target := range targets {
+		targetT := stringTransform(target, transformer)
+		if cost, _ := alignCompact(sourceR, []rune(targetT)); cost >= 0 {
+			r = append(r, Rank{source, target, cost, index})
+		}
+	}
+
+	return r
+}
+
+// MatchPositionsAligned is a version of MatchPositions that reports the most
+// compact placement of source in target, see RankMatchAligned.
+func MatchPositionsAligned(source, target string) []int {
+	return matchPositionsAligned(source, target, noopTransformer())
+}
+
+// MatchPositionsAlignedFold is a case-insensitive version of MatchPositionsAligned.
+func MatchPositionsAlignedFold(source, target string) []int {
+	return matchPositionsAligned(source, target, foldTransformer())
+}
+
+func matchPositionsAligned(source, target string, transformer transform.Transformer) []int {
+	sourceT := stringTransform(source, transformer)
+	targetT, offsets := stringTransformOffsets(target, transformer)
+
+	_, indices := alignCompact([]rune(sourceT), []rune(targetT))
+	if indices == nil {
+		return nil
+	}
+
+	positions := make([]int, 0, len(indices))
+	for _, i := range indices {
+		positions = appendPosition(positions, offsets[i])
+	}
+	return positions
+}
+
+// alignCompact finds the placement of source in target with the lowest cost,
+// where moving from a match at index k to the next one at index j costs
+// nothing if j == k+1 and j-k otherwise, i.e. the skipped runes plus one for
+// the gap. It returns the cost and the matched indices, or -1 and nil if
+// source isn't a subsequence of target.
+func alignCompact(source, target []rune) (int, []int) {
+	return alignRunes(source, target, func(k, j int) int {
+		if k < 0 || j == k+1 {
+			return 0
+		}
+		return j - k
+	})
+}
+
+// alignRunes finds the placement of source in target with the lowest total
+// cost using dynamic programming, where cost(k, j) is the cost of matching a
+// rune at index j after the previous match at index k, or k = -1 for the
+// first rune. For k < j-1, the cost has to be the sum of a function of k and
+// a function of j, so that the cheapest earlier match to jump from is the
+// same for every j, which keeps the search linear in the length of target.
+// It returns the cost and the matched indices, or -1 and nil if source isn't
+// a subsequence of target.
+func alignRunes(source, target []rune, cost func(k, j int) int) (int, []int) {
+	const inf = int(^uint(0) >> 1)
+	n, m := len(source), len(target)
+
+	if n > m {
+		return -1, nil
+	}
+	if n == 0 {
+		return 0, []int{}
+	}
+
+	// best[i][j] is the lowest cost of matching source[:i+1] with source[i]
+	// at target[j], and from[i][j] is the index of source[i-1] in that
+	// placement.
+	best := make([][]int, n)
+	from := make([][]int, n)
+
+	for i := range source {
+		best[i] = make([]int, m)
+		from[i] = make([]int, m)
+
+		// The cheapest k < j-1 to jump from, compared at the fixed index m.
+		prefix, prefixAt := inf, -1
+
+		for j := range target {
+			best[i][j] = inf
+			if i > 0 && j >= 2 && best[i-1][j-2] < inf {
+				if c := best[i-1][j-2] + cost(j-2, m); c < prefix {
+					prefix, prefixAt = c, j-2
+				}
+			}
+			if source[i] != target[j] {
+				continue
+			}
+			if i == 0 {
+				best[i][j] = cost(-1, j)
+				from[i][j] = -1
+				continue
+			}
+			if j >= 1 && best[i-1][j-1] < inf {
+				best[i][j] = best[i-1][j-1] + cost(j-1, j)
+				from[i][j] = j - 1
+			}
+			if prefixAt >= 0 {
+				if c := best[i-1][prefixAt] + cost(prefixAt, j); c < best[i][j] {
+					best[i][j] = c
+					from[i][j] = prefixAt
+				}
+			}
+		}
+	}
+
+	end, total := -1, inf
+	for j, c := range best[n-1] {
+		if c < total {
+			end, total = j, c
+		}
+	}
+	if end < 0 {
+		return -1, nil
+	}
+
+	indices := make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		indices[i] = end
+		end = from[i][end]
+	}
+
+	return total, indices
+}
@@ -0,0 +1,166 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var alignTests = []struct {
+	source    string
+	target    string
+	rank      int
+	positions []int
+}{
+	{"ab", "a...xab", 0, []int{5, 6}},
+	{"twl", "cartwheel", 4, []int{3, 4, 8}},
+	{"whl", "cartwheel", 3, []int{4, 5, 8}},
+	{"abc", "abxbcxabc", 0, []int{6, 7, 8}},
+	{"abc", "axbc", 2, []int{0, 2, 3}},
+	{"abc", "axbxc", 4, []int{0, 2, 4}},
+	{"", "abc", 0, []int{}},
+	{"dog", "cartwheel", -1, nil},
+	{"中国", "中华人民共和国中国", 0, []int{21, 24}},
+}
+
+func TestRankMatchAligned(t *testing.T) {
+	for _, val := range alignTests {
+		if rank := RankMatchAligned(val.source, val.target); rank != val.rank {
+			t.Errorf("expected ranking %d, got %d for %s in %s",
+				val.rank, rank, val.source, val.target)
+		}
+		if rank := RankMatchAlignedFold(strings.ToUpper(val.source), val.target); rank != val.rank {
+			t.Errorf("expected folded ranking %d, got %d for %s in %s",
+				val.rank, rank, val.source, val.target)
+		}
+	}
+}
+
+func TestMatchPositionsAligned(t *testing.T) {
+	for _, val := range alignTests {
+		if positions := MatchPositionsAligned(val.source, val.target); !reflect.DeepEqual(positions, val.positions) {
+			t.Errorf("expected positions %v, got %v for %s in %s",
+				val.positions, positions, val.source, val.target)
+		}
+	}
+	if positions, wanted := MatchPositionsAlignedFold("AB", "a..AB"), []int{3, 4}; !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+}
+
+func TestMatchPositionsAlignedAgreesWithMatch(t *testing.T) {
+	for _, val := range fuzzyTests {
+		positions := MatchPositionsAligned(val.source, val.target)
+		if (positions != nil) != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got positions %v",
+				val.source, val.target, val.wanted, positions)
+		}
+	}
+}
+
+// alignBruteForce returns the lowest cost of any placement of source in
+// target, or -1 if there is none.
+func alignBruteForce(source, target []rune, cost func(k, j int) int) int {
+	if len(source) == 0 {
+		return 0
+	}
+	lowest := -1
+	var try func(i, k, total int)
+	try = func(i, k, total int) {
+		if i == len(source) {
+			if lowest < 0 || total < lowest {
+				lowest = total
+			}
+			return
+		}
+		for j := k + 1; j < len(target); j++ {
+			if source[i] == target[j] {
+				try(i+1, j, total+cost(k, j))
+			}
+		}
+	}
+	try(0, -1, 0)
+	return lowest
+}
+
+func TestAlignRunesAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func(n int) []rune {
+		w := make([]rune, n)
+		for i := range w {
+			w[i] = rune("ab/"[rng.Intn(3)])
+		}
+		return w
+	}
+
+	for n := 0; n < 1000; n++ {
+		source, target := word(rng.Intn(4)), word(rng.Intn(10))
+
+		costs := map[string]func(k, j int) int{
+			"compact": func(k, j int) int {
+				if k < 0 || j == k+1 {
+					return 0
+				}
+				return j - k
+			},
+			"path": func(k, j int) int {
+				c := j % 3
+				if k < 0 || j != k+1 {
+					c += 2
+				}
+				return c
+			},
+		}
+
+		for name, cost := range costs {
+			wanted := alignBruteForce(source, target, cost)
+			total, indices := alignRunes(source, target, cost)
+			if total != wanted {
+				t.Fatalf("%s cost of %q in %q: expected %d, got %d", name, string(source), string(target), wanted, total)
+			}
+			if indices == nil {
+				continue
+			}
+			sum := 0
+			for i, j := range indices {
+				k := -1
+				if i > 0 {
+					k = indices[i-1]
+				}
+				if source[i] != target[j] || j <= k {
+					t.Fatalf("%s placement of %q in %q is invalid: %v", name, string(source), string(target), indices)
+				}
+				sum += cost(k, j)
+			}
+			if sum != total {
+				t.Errorf("%s placement of %q in %q costs %d, expected %d", name, string(source), string(target), sum, total)
+			}
+		}
+	}
+}
+
+func TestRankFindAligned(t *testing.T) {
+	targets := []string{"cartwheel", "foobar", "wheel", "WHEEL"}
+
+	ranks := RankFindAligned("whl", targets)
+	wanted := Ranks{{"whl", "cartwheel", 3, 0}, {"whl", "wheel", 3, 2}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	ranks = RankFindAlignedFold("whee", targets)
+	wanted = Ranks{{"whee", "cartwheel", 0, 0}, {"whee", "wheel", 0, 2}, {"whee", "WHEEL", 0, 3}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func ExampleMatchPositionsAligned() {
+	fmt.Println(MatchPositions("ab", "a...xab"))
+	fmt.Println(MatchPositionsAligned("ab", "a...xab"))
+	// Output:
+	// [0 6]
+	// [5 6]
+}
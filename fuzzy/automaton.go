@@ -0,0 +1,327 @@
+package fuzzy
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A LevenshteinAutomaton is a deterministic finite automaton accepting the
+// strings within a maximum Levenshtein distance of a query. Testing a string
+// takes time linear in its length, and since the automaton is fed one rune at
+// a time, it can be intersected with a sorted dictionary or a Trie, skipping
+// every word with a prefix that can't lead to a match.
+//
+// The states are the rows of the dynamic programming matrix used by
+// LevenshteinDistance, with the distances capped at the maximum distance plus
+// one. Building the automaton takes time and memory growing quickly with the
+// length of the query and the maximum distance, which is usually 1 or 2.
+//
+// A LevenshteinAutomaton is immutable and safe for concurrent use.
+type LevenshteinAutomaton struct {
+	query       string
+	maxDistance int
+
+	// classes maps the runes of the query to their character class. All
+	// other runes are in class 0.
+	classes    map[rune]int
+	numClasses int
+
+	// transitions[state*numClasses+class] is the next state, or -1 if no
+	// string starting with the runes read so far matches.
+	transitions []int32
+
+	// distances[state] is the distance between the query and the runes read
+	// so far, or maxDistance+1 if it's larger than maxDistance.
+	distances []int
+}
+
+// NewLevenshteinAutomaton builds an automaton accepting the strings within
+// maxDistance edits of query. If transpositions is true, swapping two
+// adjacent runes counts as a single edit, as in the optimal string alignment
+// distance, where, unlike DamerauLevenshteinDistance, a transposed pair can't
+// be edited any further.
+func NewLevenshteinAutomaton(query string, maxDistance int, transpositions bool) *LevenshteinAutomaton {
+	a := &LevenshteinAutomaton{
+		query:       query,
+		maxDistance: maxDistance,
+		classes:     make(map[rune]int),
+	}
+	if maxDistance < 0 {
+		return a
+	}
+
+	// Character classes are numbered from 1, 0 being all other runes.
+	source := []rune(query)
+	class := make([]int, len(source))
+	for i, r := range source {
+		if _, ok := a.classes[r]; !ok {
+			a.classes[r] = len(a.classes) + 1
+		}
+		class[i] = a.classes[r]
+	}
+	a.numClasses = len(a.classes) + 1
+
+	type state struct {
+		row, prev []int
+		prevClass int // class of the last rune read, for transpositions
+	}
+
+	limit := maxDistance + 1
+	capped := func(d int) int {
+		return min2(d, limit)
+	}
+
+	// key encodes a state for deduplication. The previous row and rune only
+	// matter when transpositions are enabled.
+	key := func(s state) string {
+		var b []byte
+		for _, d := range s.row {
+			b = binary.AppendUvarint(b, uint64(d))
+		}
+		if transpositions {
+			for _, d := range s.prev {
+				b = binary.AppendUvarint(b, uint64(d))
+			}
+			b = binary.AppendUvarint(b, uint64(s.prevClass))
+		}
+		return string(b)
+	}
+
+	start := state{row: make([]int, len(source)+1)}
+	for i := range start.row {
+		start.row[i] = capped(i)
+	}
+
+	states := []state{start}
+	index := map[string]int{key(start): 0}
+
+	for n := 0; n < len(states); n++ {
+		s := states[n]
+		a.distances = append(a.distances, s.row[len(source)])
+
+		for c := 0; c < a.numClasses; c++ {
+			next := make([]int, len(s.row))
+			next[0] = capped(s.row[0] + 1)
+			live := next[0] < limit
+
+			for i := 1; i < len(next); i++ {
+				cost := 1
+				if c != 0 && class[i-1] == c {
+					cost = 0
+				}
+				d := min(s.row[i]+1, next[i-1]+1, s.row[i-1]+cost)
+				if transpositions && i >= 2 && s.prev != nil && c != 0 &&
+					class[i-1] == s.prevClass && class[i-2] == c {
+					d = min2(d, s.prev[i-2]+1)
+				}
+				next[i] = capped(d)
+				live = live || next[i] < limit
+			}
+
+			if !live {
+				a.transitions = append(a.transitions, -1)
+				continue
+			}
+
+			t := state{row: next}
+			if transpositions {
+				t.prev = s.row
+				t.prevClass = c
+			}
+			k := key(t)
+			m, ok := index[k]
+			if !ok {
+				m = len(states)
+				index[k] = m
+				states = append(states, t)
+			}
+			a.transitions = append(a.transitions, int32(m))
+		}
+	}
+
+	return a
+}
+
+// Query returns the query the automaton was built for.
+func (a *LevenshteinAutomaton) Query() string {
+	return a.query
+}
+
+// NumStates returns the number of states of the automaton.
+func (a *LevenshteinAutomaton) NumStates() int {
+	return len(a.distances)
+}
+
+// Start returns the initial state, or -1 if the automaton doesn't accept
+// anything.
+func (a *LevenshteinAutomaton) Start() int {
+	if len(a.distances) == 0 {
+		return -1
+	}
+	return 0
+}
+
+// Step returns the state after reading r in state, or -1 if no string
+// starting with the runes read so far is accepted. Step(-1, r) returns -1.
+func (a *LevenshteinAutomaton) Step(state int, r rune) int {
+	if state < 0 {
+		return -1
+	}
+	return int(a.transitions[state*a.numClasses+a.classes[r]])
+}
+
+// CanMatch reports whether there is a string starting with the runes read
+// to reach state that is accepted.
+func (a *LevenshteinAutomaton) CanMatch(state int) bool {
+	return state >= 0
+}
+
+// IsMatch reports whether the runes read to reach state are accepted.
+func (a *LevenshteinAutomaton) IsMatch(state int) bool {
+	return state >= 0 && a.distances[state] <= a.maxDistance
+}
+
+// Match reports whether s is within the maximum distance of the query.
+func (a *LevenshteinAutomaton) Match(s string) bool {
+	return a.Distance(s) >= 0
+}
+
+// Distance returns the distance between the query and s, or -1 if it's
+// larger than the maximum distance. Without transpositions, it's the same as
+// LevenshteinDistance.
+func (a *LevenshteinAutomaton) Distance(s string) int {
+	state := a.Start()
+	for _, r := range s {
+		if state = a.Step(state, r); state < 0 {
+			return -1
+		}
+	}
+	if !a.IsMatch(state) {
+		return -1
+	}
+	return a.distances[state]
+}
+
+// RankFindSorted returns the words accepted by the automaton, with their
+// distance, in the order of words, which have to be sorted in increasing
+// order. The states of the common prefix of consecutive words are reused,
+// and words with a prefix that can't match are skipped without being read.
+func (a *LevenshteinAutomaton) RankFindSorted(words []string) Ranks {
+	start := a.Start()
+	if start < 0 {
+		return nil
+	}
+
+	var r Ranks
+
+	// states[i] is the state after the first i runes of prefix, which are
+	// at the byte offsets in offsets[i].
+	states := []int{start}
+	offsets := []int{0}
+	prefix := ""
+
+Words:
+	for i := 0; i < len(words); {
+		word := words[i]
+
+		// Reuse the states of the runes in common with the previous word.
+		n := 0
+		for n+1 < len(offsets) && sameRune(prefix[offsets[n]:], word[offsets[n]:]) {
+			n++
+		}
+		states, offsets = states[:n+1], offsets[:n+1]
+		prefix = word
+
+		for offset := offsets[n]; offset < len(word); {
+			rn, size := utf8.DecodeRuneInString(word[offset:])
+			offset += size
+
+			state := a.Step(states[len(states)-1], rn)
+			if state < 0 {
+				i = skipPrefix(words, i, word[:offset], rn == utf8.RuneError && size == 1)
+				continue Words
+			}
+			states = append(states, state)
+			offsets = append(offsets, offset)
+		}
+
+		if state := states[len(states)-1]; a.IsMatch(state) {
+			r = append(r, Rank{a.query, word, a.distances[state], i})
+		}
+		i++
+	}
+
+	return r
+}
+
+// sameRune reports whether s and t start with the same rune, decoded the
+// same way.
+func sameRune(s, t string) bool {
+	r1, size1 := utf8.DecodeRuneInString(s)
+	r2, size2 := utf8.DecodeRuneInString(t)
+	return size1 > 0 && r1 == r2 && size1 == size2 && s[:size1] == t[:size2]
+}
+
+// skipPrefix returns the index of the first word after words[i] that doesn't
+// start with prefix, which words[i] starts with. If the last rune of prefix is
+// an invalid byte, it may decode as part of a valid rune in other words, so
+// they are compared rune by rune instead of using a binary search.
+func skipPrefix(words []string, i int, prefix string, invalid bool) int {
+	if invalid {
+		runes := []rune(prefix)
+		for i++; i < len(words); i++ {
+			w := []rune(words[i])
+			if len(w) < len(runes) || string(w[:len(runes)]) != string(runes) {
+				break
+			}
+		}
+		return i
+	}
+
+	return i + 1 + sort.Search(len(words)-i-1, func(j int) bool {
+		w := words[i+1+j]
+		return w > prefix && !strings.HasPrefix(w, prefix)
+	})
+}
+
+// RankFindTrie returns the words in t accepted by the automaton, sorted like
+// Trie.Complete, with the distance of the whole word. The automaton reads the
+// words of t as stored, so for a trie created by NewTrieFold, the query has
+// to be lowercased too.
+func (a *LevenshteinAutomaton) RankFindTrie(t *Trie) []Completion {
+	start := a.Start()
+	if start < 0 {
+		return nil
+	}
+
+	var completions []Completion
+	a.walkTrie(&t.root, start, &completions)
+
+	sort.Slice(completions, func(i, j int) bool {
+		x, y := completions[i], completions[j]
+		if x.Distance != y.Distance {
+			return x.Distance < y.Distance
+		}
+		if x.Weight != y.Weight {
+			return x.Weight > y.Weight
+		}
+		return x.Word < y.Word
+	})
+
+	return completions
+}
+
+func (a *LevenshteinAutomaton) walkTrie(n *trieNode, state int, completions *[]Completion) {
+	if a.IsMatch(state) {
+		for _, e := range n.entries {
+			*completions = append(*completions, Completion{e.word, a.distances[state], e.weight})
+		}
+	}
+	for _, e := range n.edges {
+		if next := a.Step(state, e.r); next >= 0 {
+			a.walkTrie(e.node, next, completions)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"unicode/utf8"
+)
+
+// osaDistance is the optimal string alignment distance, computed with the
+// full dynamic programming matrix.
+func osaDistance(s, t string) int {
+	r1, r2 := []rune(s), []rune(t)
+	d := make([][]int, len(r1)+1)
+	for i := range d {
+		d[i] = make([]int, len(r2)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(r1); i++ {
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && r1[i-1] == r2[j-2] && r1[i-2] == r2[j-1] {
+				d[i][j] = min2(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(r1)][len(r2)]
+}
+
+func TestLevenshteinAutomatonDistance(t *testing.T) {
+	var automatonTests = []struct {
+		query, s       string
+		maxDistance    int
+		transpositions bool
+		wanted         int
+	}{
+		{"kitten", "sitting", 3, false, 3},
+		{"kitten", "sitting", 2, false, -1},
+		{"kitten", "kitten", 0, false, 0},
+		{"ab", "ba", 1, false, -1},
+		{"ab", "ba", 1, true, 1},
+		{"ca", "abc", 2, true, -1},
+		{"", "ab", 2, false, 2},
+		{"ab", "", 2, true, 2},
+		{"Schüßler", "Schüsler", 1, false, 1},
+		{"abc", "abc", -1, false, -1},
+	}
+
+	for _, test := range automatonTests {
+		a := NewLevenshteinAutomaton(test.query, test.maxDistance, test.transpositions)
+		if d := a.Distance(test.s); d != test.wanted {
+			t.Errorf("got %d, expected %d for %s and %s within %d", d, test.wanted, test.query, test.s, test.maxDistance)
+		}
+		if a.Match(test.s) != (test.wanted >= 0) {
+			t.Errorf("unexpected Match for %s and %s", test.query, test.s)
+		}
+	}
+}
+
+func TestLevenshteinAutomatonRankFindSorted(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := randomWords(rng, 1000)
+	words = append(words, "a\xe2", "a\xe2\x82\xac", "\xff", "ab\xffc")
+	sort.Strings(words)
+
+	for _, query := range randomWords(rng, 50) {
+		for k := 0; k <= 2; k++ {
+			for _, transpositions := range []bool{false, true} {
+				distance := LevenshteinDistance
+				if transpositions {
+					distance = osaDistance
+				}
+
+				var wanted Ranks
+				for i, w := range words {
+					if d := distance(query, w); d <= k {
+						wanted = append(wanted, Rank{query, w, d, i})
+					}
+				}
+
+				a := NewLevenshteinAutomaton(query, k, transpositions)
+				if got := a.RankFindSorted(words); !reflect.DeepEqual(got, wanted) {
+					t.Fatalf("RankFindSorted(%q, %d, %v): got %v, expected %v", query, k, transpositions, got, wanted)
+				}
+			}
+		}
+	}
+}
+
+func TestLevenshteinAutomatonRankFindTrie(t *testing.T) {
+	trie := NewTrie()
+	for word, weight := range map[string]float64{"cart": 1, "cat": 2, "chat": 3, "coat": 4, "dog": 5, "act": 6} {
+		trie.Add(word, weight)
+	}
+
+	a := NewLevenshteinAutomaton("cta", 1, true)
+	wanted := []Completion{{"cat", 1, 2}}
+	if got := a.RankFindTrie(trie); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("got %v, expected %v", got, wanted)
+	}
+
+	a = NewLevenshteinAutomaton("cat", 1, false)
+	wanted = []Completion{{"cat", 0, 2}, {"coat", 1, 4}, {"chat", 1, 3}, {"cart", 1, 1}}
+	if got := a.RankFindTrie(trie); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("got %v, expected %v", got, wanted)
+	}
+}
+
+func FuzzLevenshteinAutomaton(f *testing.F) {
+	f.Add("kitten", "sitting", 2, false)
+	f.Add("ab", "ba", 1, true)
+	f.Fuzz(func(t *testing.T, query, s string, k int, transpositions bool) {
+		if utf8.RuneCountInString(query) > 12 {
+			return
+		}
+		k %= 3
+		if k < 0 {
+			k = -k
+		}
+
+		distance := LevenshteinDistance(query, s)
+		if transpositions {
+			distance = osaDistance(query, s)
+		}
+		if distance > k {
+			distance = -1
+		}
+
+		a := NewLevenshteinAutomaton(query, k, transpositions)
+		if d := a.Distance(s); d != distance {
+			t.Fatalf("Distance(%q, %q, %d, %v): got %d, expected %d", query, s, k, transpositions, d, distance)
+		}
+	})
+}
+
+func ExampleLevenshteinAutomaton_RankFindSorted() {
+	words := []string{"cartwheel", "chart", "heart", "start", "stat", "tart"}
+	a := NewLevenshteinAutomaton("strat", 1, true)
+	for _, r := range a.RankFindSorted(words) {
+		fmt.Println(r.Target, r.Distance)
+	}
+	// Output:
+	// start 1
+	// stat 1
+}
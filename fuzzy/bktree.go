@@ -0,0 +1,116 @@
+package fuzzy
+
+import (
+	"sort"
+)
+
+// A BKTree is a Burkhard-Keller tree of words, which finds the words within
+// a given distance of a query without computing the distance to every word.
+// The distance has to be a metric, i.e. satisfy the triangle inequality, like
+// LevenshteinDistance and DamerauLevenshteinDistance do.
+//
+// A BKTree is safe for concurrent searches, but Add must not be called
+// concurrently with any other method.
+type BKTree struct {
+	distance DistanceFunc
+	root     *bkNode
+	size     int
+	added    int // number of calls to Add, including duplicates
+}
+
+type bkNode struct {
+	word     string
+	index    int // position among the added words, including duplicates
+	children map[int]*bkNode
+}
+
+// NewBKTree returns a BKTree measuring distances with distance, or
+// LevenshteinDistance if it is nil, and adds words to it.
+func NewBKTree(distance DistanceFunc, words ...string) *BKTree {
+	if distance == nil {
+		distance = LevenshteinDistance
+	}
+	t := &BKTree{distance: distance}
+	for _, word := range words {
+		t.Add(word)
+	}
+	return t
+}
+
+// Add adds word to the tree. It reports whether the word was added, i.e.
+// whether it wasn't in the tree already. Every call counts towards the
+// OriginalIndex of later words, so that for a tree created by NewBKTree, the
+// OriginalIndex of a word is its index in words. A word added again keeps the
+// index of its first occurrence.
+func (t *BKTree) Add(word string) bool {
+	index := t.added
+	t.added++
+
+	if t.root == nil {
+		t.root = &bkNode{word: word, index: index}
+		t.size++
+		return true
+	}
+
+	n := t.root
+	for {
+		d := t.distance(word, n.word)
+		if d == 0 && word == n.word {
+			return false
+		}
+		child, ok := n.children[d]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*bkNode)
+			}
+			n.children[d] = &bkNode{word: word, index: index}
+			t.size++
+			return true
+		}
+		n = child
+	}
+}
+
+// Len returns the number of words in the tree.
+func (t *BKTree) Len() int {
+	return t.size
+}
+
+// Search returns the words within maxDistance of word, sorted by distance.
+// Words at the same distance are in the order they were added, see Add for
+// their OriginalIndex.
+func (t *BKTree) Search(word string, maxDistance int) Ranks {
+	if t.root == nil || maxDistance < 0 {
+		return nil
+	}
+
+	var r Ranks
+
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := t.distance(word, n.word)
+		if d <= maxDistance {
+			r = append(r, Rank{word, n.word, d, n.index})
+		}
+
+		// By the triangle inequality, only the children at a distance in
+		// [d-maxDistance, d+maxDistance] from n can be close enough.
+		for cd, child := range n.children {
+			if cd >= d-maxDistance && cd <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Distance != r[j].Distance {
+			return r[i].Distance < r[j].Distance
+		}
+		return r[i].OriginalIndex < r[j].OriginalIndex
+	})
+
+	return r
+}
@@ -0,0 +1,106 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBKTreeAdd(t *testing.T) {
+	tree := NewBKTree(nil, "book", "books", "cake")
+	if tree.Len() != 3 {
+		t.Errorf("expected 3 words, got %d", tree.Len())
+	}
+	if tree.Add("book") {
+		t.Errorf("expected duplicate word not to be added")
+	}
+	if !tree.Add("boo") || tree.Len() != 4 {
+		t.Errorf("expected new word to be added")
+	}
+}
+
+func TestBKTreeSearch(t *testing.T) {
+	tree := NewBKTree(nil, "book", "books", "cake", "boo", "boon", "cook", "cape", "cart")
+
+	var searchTests = []struct {
+		word        string
+		maxDistance int
+		wanted      []string
+	}{
+		{"book", 0, []string{"book"}},
+		{"book", 1, []string{"book", "books", "boo", "boon", "cook"}},
+		{"caqe", 1, []string{"cake", "cape"}},
+		{"xyz", 2, nil},
+		{"book", -1, nil},
+	}
+
+	for _, test := range searchTests {
+		var words []string
+		for _, r := range tree.Search(test.word, test.maxDistance) {
+			words = append(words, r.Target)
+		}
+		if !reflect.DeepEqual(words, test.wanted) {
+			t.Errorf("got %q, expected %q for %s within %d", words, test.wanted, test.word, test.maxDistance)
+		}
+	}
+
+	if r := NewBKTree(nil).Search("book", 3); r != nil {
+		t.Errorf("expected no results from an empty tree, got %v", r)
+	}
+}
+
+func TestBKTreeAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := randomWords(rng, 500)
+
+	for _, distance := range []DistanceFunc{LevenshteinDistance, DamerauLevenshteinDistance} {
+		tree := NewBKTree(distance, words...)
+
+		// The brute force result, with only the first of duplicate words.
+		seen := make(map[string]bool)
+
+		for _, word := range randomWords(rng, 50) {
+			for k := 0; k <= 3; k++ {
+				var want Ranks
+				clear(seen)
+				for i, w := range words {
+					if seen[w] {
+						continue
+					}
+					seen[w] = true
+					if d := distance(word, w); d <= k {
+						want = append(want, Rank{word, w, d, i})
+					}
+				}
+				sort.Stable(want)
+
+				if got := tree.Search(word, k); !reflect.DeepEqual(got, want) {
+					t.Fatalf("Search(%q, %d): got %v, expected %v", word, k, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBKTreeOriginalIndex(t *testing.T) {
+	tree := NewBKTree(nil, "book", "cake", "book", "boo")
+	tree.Add("cake")
+	tree.Add("books")
+
+	wanted := Ranks{{"bok", "book", 1, 0}, {"bok", "boo", 1, 3}, {"bok", "books", 2, 5}}
+	if r := tree.Search("bok", 2); !reflect.DeepEqual(r, wanted) {
+		t.Errorf("expected %v, got %v", wanted, r)
+	}
+}
+
+func ExampleBKTree_Search() {
+	tree := NewBKTree(nil, "book", "books", "cake", "boo", "cape", "cart")
+	for _, r := range tree.Search("bok", 1) {
+		fmt.Println(r.Target, r.Distance)
+	}
+	// Output:
+	// book 1
+	// boo 1
+}
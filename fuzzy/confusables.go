@@ -0,0 +1,131 @@
+package fuzzy
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:generate go run gen_confusables.go
+
+// confusablesData holds the prototype mappings of Unicode Technical Standard
+// #39, see gen_confusables.go for the Unicode version.
+//
+//go:embed confusables.txt
+var confusablesData string
+
+var (
+	confusablesOnce sync.Once
+	confusables     map[rune]string
+)
+
+// confusablesTable returns the prototype mappings parsed from
+// confusables.txt.
+func confusablesTable() map[rune]string {
+	confusablesOnce.Do(func() {
+		confusables = parseConfusables(confusablesData)
+	})
+	return confusables
+}
+
+func parseConfusables(data string) map[rune]string {
+	table := make(map[rune]string)
+
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source, ok := parseCodePoints(fields[0])
+		if !ok || utf8.RuneCountInString(source) != 1 {
+			continue
+		}
+		prototype, ok := parseCodePoints(fields[1])
+		if !ok {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(source)
+		table[r] = prototype
+	}
+
+	return table
+}
+
+// parseCodePoints parses a space separated list of hexadecimal code points.
+func parseCodePoints(s string) (string, bool) {
+	var b strings.Builder
+	for _, f := range strings.Fields(s) {
+		cp, err := strconv.ParseUint(f, 16, 32)
+		if err != nil {
+			return "", false
+		}
+		b.WriteRune(rune(cp))
+	}
+	return b.String(), b.Len() > 0
+}
+
+// ConfusablesTransformer returns a transformer that maps strings onto their
+// skeleton, see Skeleton. It can be passed to MatchTransform and its siblings,
+// or chained with other transformers, e.g. to make matching case-insensitive.
+func ConfusablesTransformer() transform.Transformer {
+	return transform.Chain(norm.NFD, prototypeTransformer{table: confusablesTable()}, norm.NFD)
+}
+
+// Skeleton returns the skeleton of s as defined by Unicode Technical Standard
+// #39. Two strings that look alike, like "paypal" and "рaypal" with a Cyrillic
+// "р", have the same skeleton. The skeleton is intended for comparisons only
+// and should not be displayed.
+func Skeleton(s string) string {
+	return stringTransform(s, ConfusablesTransformer())
+}
+
+// MatchConfusable is a version of Match that compares the skeletons of source
+// and target, so that visually confusable characters from different scripts
+// are considered equal.
+func MatchConfusable(source, target string) bool {
+	return match(source, target, ConfusablesTransformer())
+}
+
+// FindConfusable is a version of Find that compares the skeletons of source
+// and targets. See MatchConfusable.
+func FindConfusable(source string, targets []string) []string {
+	return find(source, targets, ConfusablesTransformer())
+}
+
+// prototypeTransformer replaces every rune by its prototype in table.
+type prototypeTransformer struct {
+	transform.NopResetter
+	table map[rune]string
+}
+
+func (t prototypeTransformer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+
+		if prototype, ok := t.table[r]; ok {
+			if len(prototype) > len(dst[nDst:]) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			nDst += copy(dst[nDst:], prototype)
+		} else {
+			if size > len(dst[nDst:]) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			// Copy the original bytes to keep invalid UTF-8 unchanged.
+			nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+		}
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
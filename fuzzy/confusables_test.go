@@ -0,0 +1,98 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/transform"
+)
+
+func TestSkeleton(t *testing.T) {
+	var skeletonTests = []struct {
+		s, wanted string
+	}{
+		{"paypal", "paypal"},
+		{"раураl", "paypal"}, // Cyrillic р, а, у
+		{"ΡΑΥΡΑL", "PAYPAL"}, // Greek capitals
+		{"ｐａｙｐａｌ", "paypal"}, // fullwidth
+		{"𝐩𝐚𝐲𝐩𝐚𝐥", "paypal"},
+		{"g00gle", "gOOgle"},
+		{"micro", "rnicro"},
+		{"I1|l", "llll"},
+		{"é", "é"},
+	}
+
+	for _, val := range skeletonTests {
+		if skeleton := Skeleton(val.s); skeleton != val.wanted {
+			t.Errorf("expected skeleton of %q to be %q, got %q", val.s, val.wanted, skeleton)
+		}
+	}
+}
+
+func TestConfusablesTable(t *testing.T) {
+	table := confusablesTable()
+	if len(table) < 6000 {
+		t.Errorf("expected the full confusables data, got %d mappings", len(table))
+	}
+	for _, r := range "ℓⅰⅼ०۱ԍꮐ" {
+		if _, ok := table[r]; !ok {
+			t.Errorf("expected a prototype for %q", r)
+		}
+	}
+}
+
+func TestSkeletonLong(t *testing.T) {
+	// Exercise the transformer across buffer boundaries.
+	s := strings.Repeat("раураl ", 2000)
+	wanted := strings.Repeat("paypal ", 2000)
+	if skeleton := Skeleton(s); skeleton != wanted {
+		t.Errorf("unexpected skeleton of long string")
+	}
+}
+
+func TestMatchConfusable(t *testing.T) {
+	var confusableTests = []struct {
+		source string
+		target string
+		wanted bool
+	}{
+		{"paypal", "secure-раураl.com", true},
+		{"paypal", "ΡΑΥΡΑL", false},
+		{"apple", "аррӏе", true},
+		{"modern", "rnodern", true},
+		{"google", "g00gle", false},
+		{"g00gle", "google", false},
+		{"g00gle", "gOOgle", true},
+	}
+
+	for _, val := range confusableTests {
+		match := MatchConfusable(val.source, val.target)
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+	}
+}
+
+func TestFindConfusable(t *testing.T) {
+	targets := []string{"paypal", "раураl", "paypai", "pay-pal", "ebay"}
+	wanted := []string{"paypal", "раураl", "pay-pal"}
+
+	if matches := FindConfusable("paypal", targets); !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+}
+
+func TestConfusablesTransformerFold(t *testing.T) {
+	tr := transform.Chain(ConfusablesTransformer(), foldTransformer())
+	if !MatchTransform("paypal", "ΡΑΥΡΑL", tr) {
+		t.Errorf("expected folded confusable match")
+	}
+}
+
+func ExampleSkeleton() {
+	fmt.Println(Skeleton("раураl") == Skeleton("paypal"))
+	// Output: true
+}
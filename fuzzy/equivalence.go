@@ -0,0 +1,77 @@
+package fuzzy
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// Predefined equivalence classes that can be passed to NewEquivalence, on
+// their own or combined with custom classes.
+var (
+	// OCRConfusions groups characters commonly mixed up by optical character
+	// recognition or when reading part numbers aloud.
+	OCRConfusions = []string{"0OoQ", "1lI|", "2Z", "5S", "6G", "8B"}
+
+	// Leetspeak groups lowercase letters with the digits and symbols used to
+	// stand in for them. Combine it with case folding to match uppercase input.
+	Leetspeak = []string{"a4@", "b8", "e3", "g9", "il1!|", "o0", "s5$", "t7+", "z2"}
+
+	// Separators makes hyphens, underscores and spaces interchangeable.
+	Separators = []string{"-_ "}
+)
+
+// An Equivalence maps runes to a representative of their equivalence class.
+// Runes that are absent from the map only match themselves.
+type Equivalence map[rune]rune
+
+// NewEquivalence returns an Equivalence where all runes in each class are
+// interchangeable. Classes that share a rune are merged, so
+// NewEquivalence("0O", "Oo") treats "0", "O" and "o" as the same character.
+// The first rune of a class is used as its representative.
+func NewEquivalence(classes ...string) Equivalence {
+	e := make(Equivalence)
+
+	for _, class := range classes {
+		rep, found := rune(0), false
+		for _, r := range class {
+			if x, ok := e[r]; ok {
+				rep, found = x, true
+				break
+			}
+		}
+		if !found {
+			for _, r := range class {
+				rep = r
+				break
+			}
+		}
+
+		for _, r := range class {
+			if x, ok := e[r]; ok && x != rep {
+				// Merge the existing class into this one.
+				for k, v := range e {
+					if v == x {
+						e[k] = rep
+					}
+				}
+			}
+			e[r] = rep
+		}
+	}
+
+	return e
+}
+
+// Transformer returns a transformer that replaces every rune by the
+// representative of its class. It can be passed to MatchTransform and its
+// siblings, or chained with other transformers.
+func (e Equivalence) Transformer() transform.Transformer {
+	return runes.Map(e.lookup)
+}
+
+func (e Equivalence) lookup(r rune) rune {
+	if x, ok := e[r]; ok {
+		return x
+	}
+	return r
+}
@@ -0,0 +1,81 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/transform"
+)
+
+func TestNewEquivalence(t *testing.T) {
+	e := NewEquivalence("0O", "Oo", "xy", "zx")
+	wanted := Equivalence{'0': '0', 'O': '0', 'o': '0', 'x': 'x', 'y': 'x', 'z': 'x'}
+
+	if !reflect.DeepEqual(e, wanted) {
+		t.Errorf("expected %q, got %q", wanted, e)
+	}
+}
+
+func TestMatchTransformEquivalence(t *testing.T) {
+	var equivalenceTests = []struct {
+		classes []string
+		source  string
+		target  string
+		wanted  bool
+	}{
+		{OCRConfusions, "P0L-1O5", "POL-10S", true},
+		{OCRConfusions, "B00K", "8OOK", true},
+		{OCRConfusions, "B00K", "BOOT", false},
+		{Leetspeak, "leet", "l33t", true},
+		{Leetspeak, "h4ck", "hacks", true},
+		{Separators, "new_york", "new york", true},
+		{Separators, "new-york", "newyork", false},
+		{append(OCRConfusions, Separators...), "ab_10", "AB ab-IO", true},
+	}
+
+	for _, val := range equivalenceTests {
+		match := MatchTransform(val.source, val.target, NewEquivalence(val.classes...).Transformer())
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+	}
+}
+
+func TestFindTransformEquivalence(t *testing.T) {
+	targets := []string{"SN-0015", "SN_OOIS", "SM-0015", "sn 0015"}
+	wanted := []string{"SN-0015", "SN_OOIS"}
+
+	matches := FindTransform("SN 0015", targets, NewEquivalence(append(OCRConfusions, Separators...)...).Transformer())
+
+	if !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+}
+
+func TestRankTransformEquivalence(t *testing.T) {
+	tr := transform.Chain(foldTransformer(), NewEquivalence(Leetspeak...).Transformer())
+
+	if rank := RankMatchTransform("L33T", "leets", tr); rank != 1 {
+		t.Errorf("expected ranking 1, got %d", rank)
+	}
+
+	ranks := RankFindTransform("1337", []string{"leet", "LEETS", "loot"}, tr)
+	wanted := Ranks{{"1337", "leet", 0, 0}, {"1337", "LEETS", 1, 1}}
+
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	positions := MatchPositionsTransform("1337", "LEETS", tr)
+	if !reflect.DeepEqual(positions, []int{0, 1, 2, 3}) {
+		t.Errorf("expected positions [0 1 2 3], got %v", positions)
+	}
+}
+
+func ExampleNewEquivalence() {
+	e := NewEquivalence(append(OCRConfusions, Separators...)...)
+	fmt.Print(MatchTransform("SN 0015", "SN-OOIS", e.Transformer()))
+	// Output: true
+}
@@ -0,0 +1,160 @@
+// Package flagsuggest adds "did you mean ...?" suggestions, found with
+// fuzzy.Suggest, to the errors for undefined flags and unknown commands of
+// command line programs.
+package flagsuggest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// An UnknownFlagError is returned by Parse for a flag that isn't defined,
+// with the defined flags the user may have meant.
+type UnknownFlagError struct {
+	// Name is the name of the flag, without leading dashes.
+	Name string
+
+	// Suggestions are the names of the closest defined flags, see
+	// fuzzy.Suggest.
+	Suggestions []string
+}
+
+func (e *UnknownFlagError) Error() string {
+	return "flag provided but not defined: -" + e.Name + didYouMean(e.Suggestions, "-%s")
+}
+
+// An UnknownCommandError is returned by LookupCommand for a command that
+// isn't in the table, with the commands the user may have meant.
+type UnknownCommandError struct {
+	// Name is the name of the command.
+	Name string
+
+	// Suggestions are the names of the closest commands, see fuzzy.Suggest.
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("unknown command %q", e.Name) + didYouMean(e.Suggestions, "%q")
+}
+
+// didYouMean formats suggestions with format, e.g. ` (did you mean "a" or
+// "b"?)`, or returns an empty string if there are none.
+func didYouMean(suggestions []string, format string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf(format, s)
+	}
+	if len(quoted) == 1 {
+		return " (did you mean " + quoted[0] + "?)"
+	}
+	return " (did you mean " + strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1] + "?)"
+}
+
+// Parse is like fs.Parse(args), except that the error for an undefined flag
+// is an *UnknownFlagError suggesting the closest defined flags, like
+// "flag provided but not defined: -verbos (did you mean -verbose?)". The
+// error is reported according to the error handling of fs, printing it and
+// the usage message to the output of fs, just like fs.Parse does. The flags
+// before the undefined one are set, as with fs.Parse.
+func Parse(fs *flag.FlagSet, args []string) error {
+	i, name, ok := findUndefined(fs, args)
+	if !ok {
+		return fs.Parse(args)
+	}
+	if err := fs.Parse(args[:i]); err != nil {
+		return err
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, f.Name)
+	})
+	err := &UnknownFlagError{name, fuzzy.Suggest(name, names, fuzzy.SuggestOptions{})}
+
+	fmt.Fprintln(fs.Output(), err)
+	if fs.Usage != nil {
+		fs.Usage()
+	} else {
+		// The same as the default usage message of flag.FlagSet.
+		if fs.Name() == "" {
+			fmt.Fprintf(fs.Output(), "Usage:\n")
+		} else {
+			fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+		}
+		fs.PrintDefaults()
+	}
+
+	switch fs.ErrorHandling() {
+	case flag.ExitOnError:
+		os.Exit(2)
+	case flag.PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// findUndefined scans args like fs.Parse does and returns the index and name
+// of the first flag that isn't defined in fs. It stops at the first
+// argument that isn't a flag, and at anything fs.Parse would stop at with an
+// error of its own, like a help flag or a missing value.
+func findUndefined(fs *flag.FlagSet, args []string) (int, string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			return 0, "", false
+		}
+		name := arg[1:]
+		if name[0] == '-' {
+			name = name[1:]
+			if name == "" { // "--" terminates the flags
+				return 0, "", false
+			}
+		}
+		if name[0] == '-' || name[0] == '=' {
+			return 0, "", false
+		}
+		name, _, hasValue := strings.Cut(name, "=")
+
+		f := fs.Lookup(name)
+		if f == nil {
+			if name == "help" || name == "h" {
+				return 0, "", false
+			}
+			return i, name, true
+		}
+
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if !hasValue {
+			i++ // the value is the next argument
+		}
+	}
+	return 0, "", false
+}
+
+// LookupCommand returns the command registered under name in commands. If
+// there is none, it returns an *UnknownCommandError suggesting the closest
+// command names, like `unknown command "biuld" (did you mean "build"?)`.
+func LookupCommand[C any](commands map[string]C, name string) (C, error) {
+	if command, ok := commands[name]; ok {
+		return command, nil
+	}
+
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var zero C
+	return zero, &UnknownCommandError{name, fuzzy.Suggest(name, names, fuzzy.SuggestOptions{})}
+}
@@ -0,0 +1,141 @@
+package flagsuggest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestFlagSet(handling flag.ErrorHandling) (*flag.FlagSet, *bytes.Buffer) {
+	fs := flag.NewFlagSet("app", handling)
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	fs.Bool("verbose", false, "print more")
+	fs.Bool("version", false, "print the version")
+	fs.String("output", "", "output `file`")
+	return fs, &out
+}
+
+func TestParse(t *testing.T) {
+	fs, out := newTestFlagSet(flag.ContinueOnError)
+	if err := Parse(fs, []string{"-verbose", "-output", "x", "arg"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if fs.Lookup("output").Value.String() != "x" || !reflect.DeepEqual(fs.Args(), []string{"arg"}) {
+		t.Errorf("flags not parsed")
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out)
+	}
+
+	fs, out = newTestFlagSet(flag.ContinueOnError)
+	err := Parse(fs, []string{"-output=y", "--verbos"})
+	var unknown *UnknownFlagError
+	if !errors.As(err, &unknown) || unknown.Name != "verbos" || !reflect.DeepEqual(unknown.Suggestions, []string{"verbose"}) {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if fs.Lookup("output").Value.String() != "y" {
+		t.Errorf("expected the flags before the undefined one to be set")
+	}
+	wanted := "flag provided but not defined: -verbos (did you mean -verbose?)\nUsage of app:\n"
+	if !strings.HasPrefix(out.String(), wanted) {
+		t.Errorf("got output %q, expected it to start with %q", out, wanted)
+	}
+
+	fs, out = newTestFlagSet(flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), "custom usage\n") }
+	if err := Parse(fs, []string{"-xyz=1"}); err == nil || err.Error() != "flag provided but not defined: -xyz" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if got := out.String(); got != "flag provided but not defined: -xyz\ncustom usage\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+
+	fs, _ = newTestFlagSet(flag.ContinueOnError)
+	if err := Parse(fs, []string{"-output"}); err == nil || errors.As(err, &unknown) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := Parse(fs, []string{"-h", "-verbos"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestParseStopsLikeFlagSet(t *testing.T) {
+	var parseTests = []struct {
+		args []string
+		rest []string
+	}{
+		{[]string{"-output", "-verbos"}, []string{}},
+		{[]string{"arg", "-verbos"}, []string{"arg", "-verbos"}},
+		{[]string{"--", "-verbos"}, []string{"-verbos"}},
+		{[]string{"-", "-verbos"}, []string{"-", "-verbos"}},
+		{[]string{"-verbose", "-verbos=x"}, nil},
+	}
+
+	for _, test := range parseTests {
+		fs, _ := newTestFlagSet(flag.ContinueOnError)
+		err := Parse(fs, test.args)
+		if test.rest == nil {
+			var unknown *UnknownFlagError
+			if !errors.As(err, &unknown) {
+				t.Errorf("expected *UnknownFlagError for %q, got %v", test.args, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(fs.Args(), test.rest) {
+			t.Errorf("got %q, %v for %q, expected %q", fs.Args(), err, test.args, test.rest)
+		}
+	}
+}
+
+func TestParsePanicOnError(t *testing.T) {
+	fs, _ := newTestFlagSet(flag.PanicOnError)
+	defer func() {
+		err, ok := recover().(error)
+		var unknown *UnknownFlagError
+		if !ok || !errors.As(err, &unknown) {
+			t.Errorf("expected panic with *UnknownFlagError, got %v", err)
+		}
+	}()
+	Parse(fs, []string{"-verison"})
+}
+
+func TestLookupCommand(t *testing.T) {
+	commands := map[string]int{"build": 1, "bench": 2, "test": 3}
+
+	if c, err := LookupCommand(commands, "test"); c != 3 || err != nil {
+		t.Errorf("got %d, %v for test", c, err)
+	}
+
+	c, err := LookupCommand(commands, "biuld")
+	if c != 0 || err == nil || err.Error() != `unknown command "biuld" (did you mean "build"?)` {
+		t.Errorf("got %d, %v for biuld", c, err)
+	}
+
+	_, err = LookupCommand(commands, "deploy")
+	if err == nil || err.Error() != `unknown command "deploy"` {
+		t.Errorf("got %v for deploy", err)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := didYouMean([]string{"a", "b", "c"}, "-%s"); got != " (did you mean -a, -b or -c?)" {
+		t.Errorf("unexpected %q", got)
+	}
+}
+
+func ExampleParse() {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+	fs.Usage = func() {}
+	fs.Bool("verbose", false, "print more")
+
+	Parse(fs, []string{"-verbos"})
+	// Output:
+	// flag provided but not defined: -verbos (did you mean -verbose?)
+}
@@ -0,0 +1,484 @@
+package fuzzy
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrKeyOrder is returned by FSTBuilder.Add for a key that isn't greater
+	// than the previous one.
+	ErrKeyOrder = errors.New("fuzzy: FST keys must be added in strictly increasing order")
+
+	// ErrInvalidKey is returned by FSTBuilder.Add for a key that isn't valid
+	// UTF-8.
+	ErrInvalidKey = errors.New("fuzzy: FST key is not valid UTF-8")
+
+	// ErrInvalidFST is returned by FST.UnmarshalBinary for malformed data.
+	ErrInvalidFST = errors.New("fuzzy: invalid FST data")
+)
+
+// An FST is an immutable dictionary mapping string keys to uint64 values,
+// stored as a minimal acyclic finite-state transducer. Keys sharing a prefix
+// or a suffix share the states and transitions for it, so it usually takes a
+// fraction of the memory of a sorted []string. The value of a key is the sum
+// of the outputs of the transitions on its path.
+//
+// Besides exact and prefix lookups, an FST can be searched for the keys that
+// a source fuzzy matches, like Find, and for the keys accepted by a
+// LevenshteinAutomaton. An FST is safe for concurrent use.
+type FST struct {
+	nodes []fstNode
+
+	// The transitions of node n are at [first, first+count) in the
+	// following slices, sorted by label.
+	labels  []rune
+	outputs []uint64
+	targets []uint32
+
+	root uint32
+	size int
+}
+
+type fstNode struct {
+	final       bool
+	finalOutput uint64
+	first       uint32
+	count       uint32
+}
+
+// An FSTEntry is a key and its value found in an FST.
+type FSTEntry struct {
+	Key   string
+	Value uint64
+
+	// Distance is the distance between the key and the query of the
+	// automaton for FST.RankFindAutomaton, and zero otherwise.
+	Distance int
+}
+
+// An FSTBuilder builds an FST from keys added in increasing order. The
+// states for a key are minimized as soon as the next key doesn't share them,
+// so memory usage is proportional to the size of the resulting FST.
+type FSTBuilder struct {
+	fst *FST
+
+	// unfinished holds the states on the path of the previous key that may
+	// still get more transitions. The last transition of every state but
+	// the last leads to the next one.
+	unfinished []*fstBuilderNode
+	registry   map[string]uint32
+	previous   []rune
+	started    bool
+}
+
+type fstBuilderNode struct {
+	final       bool
+	finalOutput uint64
+	transitions []fstTransition
+}
+
+type fstTransition struct {
+	label  rune
+	output uint64
+	target uint32
+}
+
+// NewFSTBuilder returns an empty FSTBuilder.
+func NewFSTBuilder() *FSTBuilder {
+	return &FSTBuilder{
+		fst:        &FST{},
+		unfinished: []*fstBuilderNode{{}},
+		registry:   make(map[string]uint32),
+	}
+}
+
+// Add adds key with value. Keys have to be valid UTF-8 and added in strictly
+// increasing order, otherwise Add returns ErrInvalidKey or ErrKeyOrder and
+// the key isn't added.
+func (b *FSTBuilder) Add(key string, value uint64) error {
+	if !utf8.ValidString(key) {
+		return ErrInvalidKey
+	}
+	if b.started && key <= string(b.previous) {
+		return ErrKeyOrder
+	}
+	b.started = true
+
+	runes := []rune(key)
+	prefix := 0
+	for prefix < len(runes) && prefix < len(b.previous) && runes[prefix] == b.previous[prefix] {
+		prefix++
+	}
+	b.compileFrom(prefix)
+
+	// Move as much of the value as possible to the shared prefix, pushing
+	// the part of the existing outputs that isn't shared further down.
+	for i := 0; i < prefix; i++ {
+		n := b.unfinished[i]
+		t := &n.transitions[len(n.transitions)-1]
+		common := t.output
+		if value < common {
+			common = value
+		}
+		if rest := t.output - common; rest > 0 {
+			next := b.unfinished[i+1]
+			for j := range next.transitions {
+				next.transitions[j].output += rest
+			}
+			if next.final {
+				next.finalOutput += rest
+			}
+		}
+		t.output = common
+		value -= common
+	}
+
+	if prefix == len(runes) {
+		// Only the empty key can end at an unfinished state.
+		b.unfinished[prefix].final = true
+		b.unfinished[prefix].finalOutput = value
+	} else {
+		for _, r := range runes[prefix:] {
+			n := b.unfinished[len(b.unfinished)-1]
+			n.transitions = append(n.transitions, fstTransition{label: r, output: value})
+			value = 0
+			b.unfinished = append(b.unfinished, &fstBuilderNode{})
+		}
+		b.unfinished[len(b.unfinished)-1].final = true
+	}
+
+	b.previous = runes
+	b.fst.size++
+
+	return nil
+}
+
+// compileFrom freezes the unfinished states after depth.
+func (b *FSTBuilder) compileFrom(depth int) {
+	for len(b.unfinished) > depth+1 {
+		n := b.unfinished[len(b.unfinished)-1]
+		b.unfinished = b.unfinished[:len(b.unfinished)-1]
+
+		parent := b.unfinished[len(b.unfinished)-1]
+		parent.transitions[len(parent.transitions)-1].target = b.compile(n)
+	}
+}
+
+// compile returns the index of the state equivalent to n, adding it to the
+// FST if there is none yet.
+func (b *FSTBuilder) compile(n *fstBuilderNode) uint32 {
+	var key []byte
+	if n.final {
+		key = append(key, 1)
+		key = binary.AppendUvarint(key, n.finalOutput)
+	} else {
+		key = append(key, 0)
+	}
+	for _, t := range n.transitions {
+		key = binary.AppendUvarint(key, uint64(t.label))
+		key = binary.AppendUvarint(key, t.output)
+		key = binary.AppendUvarint(key, uint64(t.target))
+	}
+	if id, ok := b.registry[string(key)]; ok {
+		return id
+	}
+
+	f := b.fst
+	id := uint32(len(f.nodes))
+	f.nodes = append(f.nodes, fstNode{n.final, n.finalOutput, uint32(len(f.labels)), uint32(len(n.transitions))})
+	for _, t := range n.transitions {
+		f.labels = append(f.labels, t.label)
+		f.outputs = append(f.outputs, t.output)
+		f.targets = append(f.targets, t.target)
+	}
+	b.registry[string(key)] = id
+
+	return id
+}
+
+// Build finishes the FST. The builder must not be used afterwards.
+func (b *FSTBuilder) Build() *FST {
+	b.compileFrom(0)
+	b.fst.root = b.compile(b.unfinished[0])
+
+	f := b.fst
+	b.fst, b.unfinished, b.registry = nil, nil, nil
+	return f
+}
+
+// Len returns the number of keys in the FST.
+func (f *FST) Len() int {
+	return f.size
+}
+
+// step returns the target and output of the transition of node n labeled r.
+func (f *FST) step(n uint32, r rune) (uint32, uint64, bool) {
+	node := f.nodes[n]
+	labels := f.labels[node.first : node.first+node.count]
+	i := sort.Search(len(labels), func(i int) bool {
+		return labels[i] >= r
+	})
+	if i == len(labels) || labels[i] != r {
+		return 0, 0, false
+	}
+	t := int(node.first) + i
+	return f.targets[t], f.outputs[t], true
+}
+
+// Get returns the value of key and whether it's in the FST.
+func (f *FST) Get(key string) (uint64, bool) {
+	n, value, ok := f.walk(key)
+	if !ok || !f.nodes[n].final {
+		return 0, false
+	}
+	return value + f.nodes[n].finalOutput, true
+}
+
+// walk returns the state after reading s from the root and the sum of the
+// outputs on the way. Since keys are valid UTF-8, invalid s isn't found.
+func (f *FST) walk(s string) (uint32, uint64, bool) {
+	if len(f.nodes) == 0 || !utf8.ValidString(s) {
+		return 0, 0, false
+	}
+
+	n, value := f.root, uint64(0)
+	for _, r := range s {
+		next, output, ok := f.step(n, r)
+		if !ok {
+			return 0, 0, false
+		}
+		n, value = next, value+output
+	}
+	return n, value, true
+}
+
+// Prefix returns the keys starting with prefix, sorted.
+func (f *FST) Prefix(prefix string) []FSTEntry {
+	n, value, ok := f.walk(prefix)
+	if !ok {
+		return nil
+	}
+
+	var entries []FSTEntry
+	f.collect(n, []rune(prefix), value, &entries)
+	return entries
+}
+
+// collect appends all keys below n, where path and value are the runes and
+// outputs leading to n.
+func (f *FST) collect(n uint32, path []rune, value uint64, entries *[]FSTEntry) {
+	node := f.nodes[n]
+	if node.final {
+		*entries = append(*entries, FSTEntry{Key: string(path), Value: value + node.finalOutput})
+	}
+	for t := node.first; t < node.first+node.count; t++ {
+		f.collect(f.targets[t], append(path, f.labels[t]), value+f.outputs[t], entries)
+	}
+}
+
+// Find returns the keys that source fuzzy matches, i.e. that contain the
+// runes of source in order, sorted. It's the same as Find with all keys as
+// targets.
+func (f *FST) Find(source string) []FSTEntry {
+	return f.find(source, false)
+}
+
+// FindFold is a case-insensitive version of Find.
+func (f *FST) FindFold(source string) []FSTEntry {
+	return f.find(source, true)
+}
+
+func (f *FST) find(source string, fold bool) []FSTEntry {
+	if len(f.nodes) == 0 {
+		return nil
+	}
+
+	runes := []rune(source)
+	if fold {
+		for i, r := range runes {
+			runes[i] = unicode.ToLower(r)
+		}
+	}
+
+	var entries []FSTEntry
+	f.findFrom(f.root, runes, nil, 0, fold, &entries)
+	return entries
+}
+
+// findFrom appends the keys below n that contain source in order, where path
+// and value are the runes and outputs leading to n.
+func (f *FST) findFrom(n uint32, source, path []rune, value uint64, fold bool, entries *[]FSTEntry) {
+	if len(source) == 0 {
+		f.collect(n, path, value, entries)
+		return
+	}
+
+	node := f.nodes[n]
+	for t := node.first; t < node.first+node.count; t++ {
+		label := f.labels[t]
+		rest := source
+		if label == source[0] || fold && unicode.ToLower(label) == source[0] {
+			// Matching greedily finds the same keys as trying all
+			// placements.
+			rest = source[1:]
+		}
+		f.findFrom(f.targets[t], rest, append(path, label), value+f.outputs[t], fold, entries)
+	}
+}
+
+// RankFindAutomaton returns the keys accepted by a, with their distance,
+// sorted by key. Only the transitions on the paths that a can still accept
+// are followed.
+func (f *FST) RankFindAutomaton(a *LevenshteinAutomaton) []FSTEntry {
+	start := a.Start()
+	if len(f.nodes) == 0 || start < 0 {
+		return nil
+	}
+
+	var entries []FSTEntry
+	f.rankFindAutomaton(f.root, a, start, nil, 0, &entries)
+	return entries
+}
+
+func (f *FST) rankFindAutomaton(n uint32, a *LevenshteinAutomaton, state int, path []rune, value uint64, entries *[]FSTEntry) {
+	node := f.nodes[n]
+	if node.final && a.IsMatch(state) {
+		*entries = append(*entries, FSTEntry{string(path), value + node.finalOutput, a.distances[state]})
+	}
+	for t := node.first; t < node.first+node.count; t++ {
+		if next := a.Step(state, f.labels[t]); next >= 0 {
+			f.rankFindAutomaton(f.targets[t], a, next, append(path, f.labels[t]), value+f.outputs[t], entries)
+		}
+	}
+}
+
+// fstMagic starts the binary encoding of an FST, followed by a version.
+const fstMagic = "fzst\x01"
+
+// MarshalBinary encodes the FST. The encoding lists the states, each with
+// its transitions, using variable-length integers.
+func (f *FST) MarshalBinary() ([]byte, error) {
+	data := []byte(fstMagic)
+	data = binary.AppendUvarint(data, uint64(len(f.nodes)))
+	data = binary.AppendUvarint(data, uint64(f.root))
+
+	for i, node := range f.nodes {
+		if node.final {
+			data = append(data, 1)
+			data = binary.AppendUvarint(data, node.finalOutput)
+		} else {
+			data = append(data, 0)
+		}
+		data = binary.AppendUvarint(data, uint64(node.count))
+		for t := node.first; t < node.first+node.count; t++ {
+			data = binary.AppendUvarint(data, uint64(f.labels[t]))
+			data = binary.AppendUvarint(data, f.outputs[t])
+			// Targets always precede their state, so the difference is
+			// positive and usually small.
+			data = binary.AppendUvarint(data, uint64(uint32(i)-f.targets[t]))
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes an FST encoded by MarshalBinary, replacing f. It
+// returns ErrInvalidFST if data is malformed.
+func (f *FST) UnmarshalBinary(data []byte) error {
+	if !strings.HasPrefix(string(data), fstMagic) {
+		return ErrInvalidFST
+	}
+	d := fstDecoder{data: data[len(fstMagic):]}
+
+	numNodes := d.uvarint()
+	root := d.uvarint()
+	// Every state takes at least two bytes.
+	if d.err || numNodes > uint64(len(d.data))/2 || (numNodes > 0 && root >= numNodes) {
+		return ErrInvalidFST
+	}
+
+	g := FST{nodes: make([]fstNode, 0, numNodes), root: uint32(root)}
+	keys := make([]int, 0, numNodes) // number of keys below each state
+
+	for i := uint64(0); i < numNodes; i++ {
+		var node fstNode
+		switch d.byte() {
+		case 0:
+		case 1:
+			node.final = true
+			node.finalOutput = d.uvarint()
+		default:
+			return ErrInvalidFST
+		}
+
+		count := d.uvarint()
+		// Every transition takes at least three bytes.
+		if d.err || count > uint64(len(d.data))/3 {
+			return ErrInvalidFST
+		}
+		node.first, node.count = uint32(len(g.labels)), uint32(count)
+
+		n := 0
+		if node.final {
+			n = 1
+		}
+		for t := uint64(0); t < count; t++ {
+			label, output, delta := d.uvarint(), d.uvarint(), d.uvarint()
+			if d.err || label > unicode.MaxRune || !utf8.ValidRune(rune(label)) || delta == 0 || delta > i {
+				return ErrInvalidFST
+			}
+			if t > 0 && rune(label) <= g.labels[len(g.labels)-1] {
+				return ErrInvalidFST
+			}
+			target := i - delta
+			g.labels = append(g.labels, rune(label))
+			g.outputs = append(g.outputs, output)
+			g.targets = append(g.targets, uint32(target))
+			n += keys[target]
+		}
+
+		g.nodes = append(g.nodes, node)
+		keys = append(keys, n)
+	}
+	if len(d.data) != 0 {
+		return ErrInvalidFST
+	}
+
+	if numNodes > 0 {
+		g.size = keys[root]
+	}
+	*f = g
+
+	return nil
+}
+
+// fstDecoder reads from data, setting err instead of failing.
+type fstDecoder struct {
+	data []byte
+	err  bool
+}
+
+func (d *fstDecoder) byte() byte {
+	if len(d.data) == 0 {
+		d.err = true
+		return 0xff
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *fstDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = true
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
@@ -0,0 +1,221 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// buildTestFST returns an FST of the sorted, distinct words and the value of
+// every key.
+func buildTestFST(t *testing.T, words []string, values func(i int) uint64) (*FST, []string, map[string]uint64) {
+	sorted := append([]string(nil), words...)
+	sort.Strings(sorted)
+
+	b := NewFSTBuilder()
+	var keys []string
+	wanted := make(map[string]uint64)
+	for _, w := range sorted {
+		if _, dup := wanted[w]; dup {
+			continue
+		}
+		v := values(len(keys))
+		if err := b.Add(w, v); err != nil {
+			t.Fatalf("unexpected error %v for %q", err, w)
+		}
+		keys = append(keys, w)
+		wanted[w] = v
+	}
+
+	return b.Build(), keys, wanted
+}
+
+func entryKeys(entries []FSTEntry) []string {
+	var keys []string
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestFSTGet(t *testing.T) {
+	f, _, wanted := buildTestFST(t, []string{"", "mon", "monday", "moon", "thursday", "tuesday", "wednesday"}, func(i int) uint64 {
+		return uint64(7 - i)
+	})
+
+	if f.Len() != 7 {
+		t.Errorf("expected 7 keys, got %d", f.Len())
+	}
+	for key, value := range wanted {
+		if v, ok := f.Get(key); !ok || v != value {
+			t.Errorf("got %d, %v, expected %d for %q", v, ok, value, key)
+		}
+	}
+	for _, key := range []string{"m", "mo", "mond", "mondays", "friday", "mon\xff"} {
+		if v, ok := f.Get(key); ok {
+			t.Errorf("got unexpected value %d for %q", v, key)
+		}
+	}
+
+	if got, want := entryKeys(f.Prefix("mo")), []string{"mon", "monday", "moon"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, expected %q", got, want)
+	}
+	if got := f.Prefix("x"); got != nil {
+		t.Errorf("expected no keys, got %v", got)
+	}
+}
+
+func TestFSTBuilderErrors(t *testing.T) {
+	b := NewFSTBuilder()
+	if err := b.Add("b", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add("a", 2); !errors.Is(err, ErrKeyOrder) {
+		t.Errorf("expected ErrKeyOrder, got %v", err)
+	}
+	if err := b.Add("b", 2); !errors.Is(err, ErrKeyOrder) {
+		t.Errorf("expected ErrKeyOrder, got %v", err)
+	}
+	if err := b.Add("c\xff", 2); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if err := b.Add("c", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	f := b.Build()
+	if got := entryKeys(f.Prefix("")); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("unexpected keys %q", got)
+	}
+
+	empty := NewFSTBuilder().Build()
+	if empty.Len() != 0 || empty.Prefix("") != nil || empty.Find("") != nil {
+		t.Errorf("expected empty FST")
+	}
+	var zero FST
+	if _, ok := zero.Get(""); ok || zero.Find("a") != nil || zero.RankFindAutomaton(NewLevenshteinAutomaton("a", 1, false)) != nil {
+		t.Errorf("expected zero FST to be empty")
+	}
+}
+
+func TestFSTAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	f, keys, wanted := buildTestFST(t, randomWords(rng, 2000), func(int) uint64 {
+		return uint64(rng.Intn(1000))
+	})
+
+	if f.Len() != len(keys) {
+		t.Fatalf("expected %d keys, got %d", len(keys), f.Len())
+	}
+	runes := 0
+	for _, k := range keys {
+		runes += len([]rune(k))
+	}
+	if len(f.labels) >= runes {
+		t.Errorf("expected fewer transitions than runes, got %d and %d", len(f.labels), runes)
+	}
+
+	all := f.Prefix("")
+	if !reflect.DeepEqual(entryKeys(all), keys) {
+		t.Fatalf("keys differ")
+	}
+	for _, e := range all {
+		if e.Value != wanted[e.Key] {
+			t.Fatalf("got %d, expected %d for %q", e.Value, wanted[e.Key], e.Key)
+		}
+	}
+
+	for _, source := range randomWords(rng, 100) {
+		if got, want := entryKeys(f.Find(source)), Find(source, keys); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Find(%q): got %q, expected %q", source, got, want)
+		}
+		if got, want := entryKeys(f.FindFold(source)), FindFold(source, keys); !reflect.DeepEqual(got, want) {
+			t.Fatalf("FindFold(%q): got %q, expected %q", source, got, want)
+		}
+
+		var prefixed []string
+		for _, k := range keys {
+			if strings.HasPrefix(k, source) {
+				prefixed = append(prefixed, k)
+			}
+		}
+		if got := entryKeys(f.Prefix(source)); !reflect.DeepEqual(got, prefixed) {
+			t.Fatalf("Prefix(%q): got %q, expected %q", source, got, prefixed)
+		}
+
+		for k := 0; k <= 2; k++ {
+			a := NewLevenshteinAutomaton(source, k, k == 2)
+			var want []FSTEntry
+			for _, r := range a.RankFindSorted(keys) {
+				want = append(want, FSTEntry{r.Target, wanted[r.Target], r.Distance})
+			}
+			if got := f.RankFindAutomaton(a); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RankFindAutomaton(%q, %d): got %v, expected %v", source, k, got, want)
+			}
+		}
+	}
+}
+
+func TestFSTMarshalBinary(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	f, _, _ := buildTestFST(t, randomWords(rng, 500), func(int) uint64 {
+		return rng.Uint64()
+	})
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g FST
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&g, f) {
+		t.Errorf("decoded FST differs")
+	}
+
+	for _, bad := range [][]byte{nil, []byte("fzst"), data[:len(data)-1], append(data, 0)} {
+		if err := g.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidFST) {
+			t.Errorf("expected ErrInvalidFST, got %v", err)
+		}
+	}
+}
+
+func FuzzFSTUnmarshalBinary(f *testing.F) {
+	b := NewFSTBuilder()
+	for i, w := range []string{"cat", "cats", "dog", "dogs"} {
+		b.Add(w, uint64(i))
+	}
+	data, _ := b.Build().MarshalBinary()
+	f.Add(data)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var g FST
+		if g.UnmarshalBinary(data) != nil {
+			return
+		}
+		g.Get("cat")
+		g.Prefix("d")
+		g.Find("o")
+		g.RankFindAutomaton(NewLevenshteinAutomaton("dog", 1, false))
+	})
+}
+
+func ExampleFST() {
+	b := NewFSTBuilder()
+	b.Add("cartwheel", 3)
+	b.Add("cat", 1)
+	b.Add("wheel", 2)
+	f := b.Build()
+
+	fmt.Println(f.Get("cat"))
+	fmt.Println(f.Find("whl"))
+	fmt.Println(f.RankFindAutomaton(NewLevenshteinAutomaton("wheal", 1, false)))
+	// Output:
+	// 1 true
+	// [{cartwheel 3 0} {wheel 2 0}]
+	// [{wheel 2 1}]
+}
@@ -15,6 +15,8 @@ func FuzzFind(f *testing.F) {
 		FindFold(n, s)
 		FindNormalized(n, s)
 		FindNormalizedFold(n, s)
+		FindNormalizedCompat(n, s)
+		FindNormalizedCompatFold(n, s)
 		r := RankFind(n, s)
 		sort.Sort(r)
 		// No need to sort the other Rank calls;
@@ -22,6 +24,8 @@ func FuzzFind(f *testing.F) {
 		RankFindFold(n, s)
 		RankFindNormalized(n, s)
 		RankFindNormalizedFold(n, s)
+		RankFindNormalizedCompat(n, s)
+		RankFindNormalizedCompatFold(n, s)
 		if len(s) > 0 {
 			x := s[0]
 			LevenshteinDistance(n, x)
@@ -29,6 +33,9 @@ func FuzzFind(f *testing.F) {
 			MatchFold(n, x)
 			MatchNormalized(n, x)
 			MatchNormalizedFold(n, x)
+			MatchNormalizedCompat(n, x)
+			MatchNormalizedCompatFold(n, x)
+			MatchPositionsNormalizedCompatFold(n, x)
 		}
 	})
 }
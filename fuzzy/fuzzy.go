@@ -27,6 +27,14 @@ func normalizedFoldTransformer() transform.Transformer {
 	return transform.Chain(normalizeTransformer(), foldTransformer())
 }
 
+func normalizeCompatTransformer() transform.Transformer {
+	return transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC)
+}
+
+func normalizedCompatFoldTransformer() transform.Transformer {
+	return transform.Chain(normalizeCompatTransformer(), foldTransformer())
+}
+
 // Match returns true if source matches target using a fuzzy-searching
 // algorithm. Note that it doesn't implement Levenshtein distance (see
 // RankMatch instead), but rather a simplified version where there's no
@@ -51,6 +59,26 @@ func MatchNormalizedFold(source, target string) bool {
 	return match(source, target, normalizedFoldTransformer())
 }
 
+// MatchNormalizedCompat is a version of MatchNormalized that also applies
+// Unicode compatibility decomposition, so that characters like "①", "ｆｕｌｌ"
+// or "ﬀ" are equated with their plain forms.
+func MatchNormalizedCompat(source, target string) bool {
+	return match(source, target, normalizeCompatTransformer())
+}
+
+// MatchNormalizedCompatFold is a case-insensitive version of MatchNormalizedCompat.
+func MatchNormalizedCompatFold(source, target string) bool {
+	return match(source, target, normalizedCompatFoldTransformer())
+}
+
+// MatchTransform is a version of Match that applies t to both source and
+// target before matching, e.g. an Equivalence transformer or a chain of
+// transformers from golang.org/x/text. The transformer is reset before use and
+// must not be shared between goroutines.
+func MatchTransform(source, target string, t transform.Transformer) bool {
+	return match(source, target, t)
+}
+
 func match(source, target string, transformer transform.Transformer) bool {
 	sourceT := stringTransform(source, transformer)
 	targetT := stringTransform(target, transformer)
@@ -102,6 +130,22 @@ func FindNormalizedFold(source string, targets []string) []string {
 	return find(source, targets, normalizedFoldTransformer())
 }
 
+// FindNormalizedCompat is a unicode compatibility-normalized version of Find.
+func FindNormalizedCompat(source string, targets []string) []string {
+	return find(source, targets, normalizeCompatTransformer())
+}
+
+// FindNormalizedCompatFold is a unicode compatibility-normalized and case-insensitive version of Find.
+func FindNormalizedCompatFold(source string, targets []string) []string {
+	return find(source, targets, normalizedCompatFoldTransformer())
+}
+
+// FindTransform is a version of Find that applies t to source and all
+// targets before matching. See MatchTransform.
+func FindTransform(source string, targets []string, t transform.Transformer) []string {
+	return find(source, targets, t)
+}
+
 func find(source string, targets []string, transformer transform.Transformer) []string {
 	sourceT := stringTransform(source, transformer)
 
@@ -142,16 +186,34 @@ func RankMatchNormalizedFold(source, target string) int {
 	return rank(source, target, normalizedFoldTransformer())
 }
 
+// RankMatchNormalizedCompat is a unicode compatibility-normalized version of RankMatch.
+func RankMatchNormalizedCompat(source, target string) int {
+	return rank(source, target, normalizeCompatTransformer())
+}
+
+// RankMatchNormalizedCompatFold is a unicode compatibility-normalized and case-insensitive version of RankMatch.
+func RankMatchNormalizedCompatFold(source, target string) int {
+	return rank(source, target, normalizedCompatFoldTransformer())
+}
+
+// RankMatchTransform is a version of RankMatch that applies t to both source
+// and target before ranking. See MatchTransform.
+func RankMatchTransform(source, target string, t transform.Transformer) int {
+	return rank(source, target, t)
+}
+
 func rank(source, target string, transformer transform.Transformer) int {
+	// The length check has to happen after the transformation since it may
+	// change the encoded length, e.g. "①" decomposes into the shorter "1".
+	source = stringTransform(source, transformer)
+	target = stringTransform(target, transformer)
+
 	lenDiff := len(target) - len(source)
 
 	if lenDiff < 0 {
 		return -1
 	}
 
-	source = stringTransform(source, transformer)
-	target = stringTransform(target, transformer)
-
 	if lenDiff == 0 && source == target {
 		return 0
 	}
@@ -179,6 +241,12 @@ Outer:
 
 // RankFind is similar to Find, except it will also rank all matches using
 // Levenshtein distance.
+//
+// For the case-insensitive and unicode-normalized variants, the distance is
+// measured between the transformed strings and is the same as the one
+// returned by the corresponding RankMatch variant. Use
+// Ranks.WithOriginalDistance to measure it between the original strings
+// instead.
 func RankFind(source string, targets []string) Ranks {
 	return rankFind(source, targets, noopTransformer())
 }
@@ -198,6 +266,22 @@ func RankFindNormalizedFold(source string, targets []string) Ranks {
 	return rankFind(source, targets, normalizedFoldTransformer())
 }
 
+// RankFindNormalizedCompat is a unicode compatibility-normalized version of RankFind.
+func RankFindNormalizedCompat(source string, targets []string) Ranks {
+	return rankFind(source, targets, normalizeCompatTransformer())
+}
+
+// RankFindNormalizedCompatFold is a unicode compatibility-normalized and case-insensitive version of RankFind.
+func RankFindNormalizedCompatFold(source string, targets []string) Ranks {
+	return rankFind(source, targets, normalizedCompatFoldTransformer())
+}
+
+// RankFindTransform is a version of RankFind that applies t to source and all
+// targets before matching. See MatchTransform.
+func RankFindTransform(source string, targets []string, t transform.Transformer) Ranks {
+	return rankFind(source, targets, t)
+}
+
 func rankFind(source string, targets []string, transformer transform.Transformer) Ranks {
 	sourceT := stringTransform(source, transformer)
 
@@ -206,7 +290,9 @@ func rankFind(source string, targets []string, transformer transform.Transformer
 	for index, target := range targets {
 		targetT := stringTransform(target, transformer)
 		if matchTransformed(sourceT, targetT) {
-			distance := LevenshteinDistance(source, target)
+			// Measure the distance between the transformed strings, so that
+			// it agrees with the corresponding RankMatch variant.
+			distance := LevenshteinDistance(sourceT, targetT)
 			r = append(r, Rank{source, target, distance, index})
 		}
 	}
@@ -227,8 +313,54 @@ type Rank struct {
 	OriginalIndex int
 }
 
+// Similarity returns the Distance normalized to a similarity in [0, 1],
+// relative to the length of the longer of Source and Target, see
+// LevenshteinSimilarity. The lengths are those of Source and Target as given,
+// so use WithOriginalDistance first for an exact correspondence with
+// LevenshteinSimilarity on ranks of a transforming variant. It has no meaning
+// for the ranks of Query, Terms and PathQuery, whose Source is a query.
+func (r Rank) Similarity() float64 {
+	return similarity(r.Distance, max2(utf8.RuneCountInString(r.Source), utf8.RuneCountInString(r.Target)))
+}
+
+// AlignmentSimilarity is like Similarity, but normalizes the Distance by the
+// length of the alignment of Source and Target, see
+// LevenshteinAlignmentSimilarity.
+func (r Rank) AlignmentSimilarity() float64 {
+	return similarity(2*r.Distance, utf8.RuneCountInString(r.Source)+utf8.RuneCountInString(r.Target)+r.Distance)
+}
+
 type Ranks []Rank
 
+// WithOriginalDistance returns a copy of r where the Distance of each rank is
+// the Levenshtein distance between the original, untransformed Source and
+// Target. This is how RankFindFold and the other variants used to measure the
+// distance, and can be used to keep that behaviour.
+func (r Ranks) WithOriginalDistance() Ranks {
+	if r == nil {
+		return nil
+	}
+	ranks := make(Ranks, len(r))
+	for i, rank := range r {
+		rank.Distance = LevenshteinDistance(rank.Source, rank.Target)
+		ranks[i] = rank
+	}
+	return ranks
+}
+
+// MinSimilarity returns the ranks with a Similarity of at least cutoff, in
+// the same order. It can be used as a cutoff for the result of RankFind and its
+// siblings.
+func (r Ranks) MinSimilarity(cutoff float64) Ranks {
+	var filtered Ranks
+	for _, rank := range r {
+		if rank.Similarity() >= cutoff {
+			filtered = append(filtered, rank)
+		}
+	}
+	return filtered
+}
+
 func (r Ranks) Len() int {
 	return len(r)
 }
@@ -2,6 +2,8 @@ package fuzzy
 
 import (
 	"fmt"
+	"math"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -115,6 +117,52 @@ func TestFuzzyMatchNormalizedFold(t *testing.T) {
 	}
 }
 
+func TestFuzzyMatchNormalizedCompat(t *testing.T) {
+	var compatTests = []struct {
+		source string
+		target string
+		wanted bool
+	}{
+		{"1", "①", true},
+		{"full", "ｆｕｌｌ", true},
+		{"ff", "ﬀ", true},
+		{"kg", "㎏", true},
+		{"limon", "ｌｉｍóｎ", true},
+		{"FULL", "ｆｕｌｌ", false},
+		{"2", "①", false},
+	}
+
+	for _, val := range compatTests {
+		match := MatchNormalizedCompat(val.source, val.target)
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+	}
+}
+
+func TestFuzzyMatchNormalizedCompatFold(t *testing.T) {
+	var compatTests = []struct {
+		source string
+		target string
+		wanted bool
+	}{
+		{"FULL", "ｆｕｌｌ", true},
+		{"KG", "㎏", true},
+		{"Ⅻ", "xii", true},
+		{"limon", "ＬＩＭÓＮ", true},
+		{"lemon", "ＬＩＭÓＮ", false},
+	}
+
+	for _, val := range compatTests {
+		match := MatchNormalizedCompatFold(val.source, val.target)
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+	}
+}
+
 func TestFuzzyFind(t *testing.T) {
 	target := []string{"cartwheel", "foobar", "wheel", "baz", "cartwhéél"}
 	wanted := []string{"cartwheel", "wheel"}
@@ -217,6 +265,35 @@ func TestRankMatchNormalizedFold(t *testing.T) {
 	}
 }
 
+func TestRankMatchNormalizedCompat(t *testing.T) {
+	var fuzzyTests = []struct {
+		source string
+		target string
+		rank   int
+	}{
+		{"1", "①", 0},
+		{"ff", "ﬀ", 0},
+		{"ful", "ｆｕｌｌ", 1},
+		{"kg", "3㎏", 1},
+		{"FULL", "ｆｕｌｌ", -1},
+	}
+
+	for _, val := range fuzzyTests {
+		rank := RankMatchNormalizedCompat(val.source, val.target)
+		if rank != val.rank {
+			t.Errorf("expected ranking %d, got %d for %s in %s",
+				val.rank, rank, val.source, val.target)
+		}
+	}
+}
+
+func TestRankMatchFoldLength(t *testing.T) {
+	// "Ⱦ" is shorter than its lowercase form when UTF-8 encoded.
+	if rank := RankMatchFold("ⱦ", "Ⱦ"); rank != 0 {
+		t.Errorf("expected ranking 0, got %d", rank)
+	}
+}
+
 func TestRankMatchNormalizedFoldConcurrent(t *testing.T) {
 	target := strings.Split("Lorem ipsum dolor sit amet, consectetur adipiscing elit", " ")
 	source := "ips"
@@ -263,7 +340,7 @@ func TestRankFindNormalized(t *testing.T) {
 	target := []string{"limón", "limon", "lemon", "LIMON"}
 	wanted := []Rank{
 		{"limó", "limón", 1, 0},
-		{"limó", "limon", 2, 1},
+		{"limó", "limon", 1, 1},
 	}
 
 	ranks := RankFindNormalized("limó", target)
@@ -283,8 +360,8 @@ func TestRankFindNormalizedFold(t *testing.T) {
 	target := []string{"limón", "limon", "lemon", "LIMON"}
 	wanted := []Rank{
 		{"limó", "limón", 1, 0},
-		{"limó", "limon", 2, 1},
-		{"limó", "LIMON", 5, 3},
+		{"limó", "limon", 1, 1},
+		{"limó", "LIMON", 1, 3},
 	}
 
 	ranks := RankFindNormalizedFold("limó", target)
@@ -300,6 +377,89 @@ func TestRankFindNormalizedFold(t *testing.T) {
 	}
 }
 
+func TestRankFindNormalizedCompatFold(t *testing.T) {
+	target := []string{"ｆｕｌｌ", "full", "fool", "ＦＵＬＬ"}
+	wanted := []Rank{
+		{"ful", "ｆｕｌｌ", 1, 0},
+		{"ful", "full", 1, 1},
+		{"ful", "ＦＵＬＬ", 1, 3},
+	}
+
+	ranks := RankFindNormalizedCompatFold("ful", target)
+
+	if len(ranks) != len(wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	for i := range wanted {
+		if wanted[i] != ranks[i] {
+			t.Errorf("expected %+v, got %+v", wanted, ranks)
+		}
+	}
+}
+
+func TestRanksWithOriginalDistance(t *testing.T) {
+	target := []string{"limón", "limon", "lemon", "LIMON"}
+	wanted := Ranks{
+		{"limó", "limón", 1, 0},
+		{"limó", "limon", 2, 1},
+		{"limó", "LIMON", 5, 3},
+	}
+
+	ranks := RankFindNormalizedFold("limó", target).WithOriginalDistance()
+
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+	if ranks := Ranks(nil).WithOriginalDistance(); ranks != nil {
+		t.Errorf("expected nil, got %+v", ranks)
+	}
+}
+
+func TestRankMatchAndRankFindAgree(t *testing.T) {
+	variants := []struct {
+		name      string
+		rankMatch func(source, target string) int
+		rankFind  func(source string, targets []string) Ranks
+	}{
+		{"", RankMatch, RankFind},
+		{"Fold", RankMatchFold, RankFindFold},
+		{"Normalized", RankMatchNormalized, RankFindNormalized},
+		{"NormalizedFold", RankMatchNormalizedFold, RankFindNormalizedFold},
+		{"NormalizedCompat", RankMatchNormalizedCompat, RankFindNormalizedCompat},
+		{"NormalizedCompatFold", RankMatchNormalizedCompatFold, RankFindNormalizedCompatFold},
+		{"SmartCase", RankMatchSmartCase, RankFindSmartCase},
+	}
+
+	pairs := [][2]string{
+		{"CART", "cartwheel"},
+		{"cart", "CARTWHEEL"},
+		{"limó", "LIMON tart"},
+		{"limon", "límón"},
+		{"ful", "ｆｕｌｌ"},
+		{"ⱦ", "Ⱦ"},
+		{"Ⱦ", "ⱦ"},
+		{"ǅ", "ǆ"},
+		{"ﬀ", "ff"},
+	}
+	for _, val := range fuzzyTests {
+		pairs = append(pairs, [2]string{val.source, val.target}, [2]string{val.source, strings.ToUpper(val.target)})
+	}
+
+	for _, v := range variants {
+		for _, p := range pairs {
+			rank := v.rankMatch(p[0], p[1])
+			ranks := v.rankFind(p[0], []string{p[1]})
+			switch {
+			case rank < 0 && len(ranks) != 0:
+				t.Errorf("RankMatch%s(%q, %q) = -1, but RankFind%s found %+v", v.name, p[0], p[1], v.name, ranks)
+			case rank >= 0 && (len(ranks) != 1 || ranks[0].Distance != rank):
+				t.Errorf("RankMatch%s(%q, %q) = %d, but RankFind%s found %+v", v.name, p[0], p[1], rank, v.name, ranks)
+			}
+		}
+	}
+}
+
 func TestSortingRanks(t *testing.T) {
 	rs := Ranks{{"a", "b", 1, 0}, {"a", "cc", 2, 1}, {"a", "a", 0, 2}}
 	wanted := Ranks{rs[2], rs[0], rs[1]}
@@ -313,6 +473,45 @@ func TestSortingRanks(t *testing.T) {
 	}
 }
 
+func TestRankSimilarity(t *testing.T) {
+	var similarityTests = []struct {
+		rank   Rank
+		wanted float64
+	}{
+		{Rank{"whl", "wheel", 2, 0}, 0.6},
+		{Rank{"whl", "cartwheel", 6, 0}, 1 - 6.0/9},
+		{Rank{"", "", 0, 0}, 1},
+		{Rank{"ab", "ab", 5, 0}, 0},
+	}
+
+	for _, val := range similarityTests {
+		if similarity := val.rank.Similarity(); math.Abs(similarity-val.wanted) > 1e-9 {
+			t.Errorf("expected similarity %v, got %v for %+v", val.wanted, similarity, val.rank)
+		}
+	}
+}
+
+func TestRankAlignmentSimilarity(t *testing.T) {
+	for _, pair := range [][2]string{{"whl", "wheel"}, {"kitten", "sitting"}, {"", ""}, {"ab", ""}} {
+		rank := Rank{pair[0], pair[1], LevenshteinDistance(pair[0], pair[1]), 0}
+		if similarity, wanted := rank.AlignmentSimilarity(), LevenshteinAlignmentSimilarity(pair[0], pair[1]); math.Abs(similarity-wanted) > 1e-9 {
+			t.Errorf("expected alignment similarity %v, got %v for %q and %q", wanted, similarity, pair[0], pair[1])
+		}
+	}
+}
+
+func TestRanksMinSimilarity(t *testing.T) {
+	ranks := RankFind("whl", []string{"cartwheel", "foobar", "wheel", "whl"})
+	wanted := Ranks{{"whl", "wheel", 2, 2}, {"whl", "whl", 0, 3}}
+
+	if filtered := ranks.MinSimilarity(0.5); !reflect.DeepEqual(filtered, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, filtered)
+	}
+	if filtered := ranks.MinSimilarity(1.1); filtered != nil {
+		t.Errorf("expected no ranks, got %+v", filtered)
+	}
+}
+
 func BenchmarkMatch(b *testing.B) {
 	ft := fuzzyTests[2]
 	for i := 0; i < b.N; i++ {
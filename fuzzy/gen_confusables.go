@@ -0,0 +1,74 @@
+//go:build ignore
+
+// This program generates confusables.txt from the confusables data file of
+// Unicode Technical Standard #39. Run it with go generate.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// unicodeVersion is the version of the Unicode security data to use.
+const unicodeVersion = "17.0.0"
+
+const url = "https://www.unicode.org/Public/" + unicodeVersion + "/security/confusables.txt"
+
+func main() {
+	resp, err := http.Get(url)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("fetching %s: %s", url, resp.Status)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `# confusables.txt
+#
+# Prototype mappings used to compute skeletons as described in Unicode
+# Technical Standard #39, section 4 "Confusable Detection", generated by
+# gen_confusables.go from the Unicode %s data file:
+#
+#   %s
+#
+# Each line has the format
+#
+#   source ; prototype
+#
+# with code points in hexadecimal. DO NOT EDIT.
+
+`, unicodeVersion, url)
+
+	n := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source := strings.TrimPrefix(strings.TrimSpace(fields[0]), "\ufeff")
+		prototype := strings.Join(strings.Fields(fields[1]), " ")
+		fmt.Fprintf(&b, "%s ;\t%s\n", source, prototype)
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if n == 0 {
+		log.Fatalf("no mappings found in %s", url)
+	}
+
+	if err := os.WriteFile("confusables.txt", []byte(b.String()), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,99 @@
+package fuzzy
+
+import (
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// Predefined sets of runes that can be ignored while matching.
+var (
+	// IgnorePunctuation contains the Unicode punctuation characters, such as
+	// ".", "-" and "'".
+	IgnorePunctuation = runes.In(unicode.P)
+
+	// IgnoreSpace contains the Unicode white space characters.
+	IgnoreSpace = runes.In(unicode.White_Space)
+
+	// IgnoreSymbols contains the Unicode symbol characters, such as "+", "$"
+	// and "©".
+	IgnoreSymbols = runes.In(unicode.S)
+)
+
+// IgnoreAny returns a set containing the runes of all the given sets.
+func IgnoreAny(sets ...runes.Set) runes.Set {
+	return runes.Predicate(func(r rune) bool {
+		for _, s := range sets {
+			if s.Contains(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func ignoreTransformer(ignore runes.Set) transform.Transformer {
+	return runes.Remove(ignore)
+}
+
+func ignoreFoldTransformer(ignore runes.Set) transform.Transformer {
+	return transform.Chain(foldTransformer(), runes.Remove(ignore))
+}
+
+// MatchIgnoring is a version of Match that disregards the runes in ignore, in
+// both source and target. For example, "newyork" matches "New-York" when
+// ignoring IgnorePunctuation.
+func MatchIgnoring(source, target string, ignore runes.Set) bool {
+	return match(source, target, ignoreTransformer(ignore))
+}
+
+// MatchFoldIgnoring is a case-insensitive version of MatchIgnoring.
+func MatchFoldIgnoring(source, target string, ignore runes.Set) bool {
+	return match(source, target, ignoreFoldTransformer(ignore))
+}
+
+// FindIgnoring is a version of Find that disregards the runes in ignore. The
+// returned targets are unmodified.
+func FindIgnoring(source string, targets []string, ignore runes.Set) []string {
+	return find(source, targets, ignoreTransformer(ignore))
+}
+
+// FindFoldIgnoring is a case-insensitive version of FindIgnoring.
+func FindFoldIgnoring(source string, targets []string, ignore runes.Set) []string {
+	return find(source, targets, ignoreFoldTransformer(ignore))
+}
+
+// RankMatchIgnoring is a version of RankMatch that disregards the runes in
+// ignore, so they don't count towards the distance.
+func RankMatchIgnoring(source, target string, ignore runes.Set) int {
+	return rank(source, target, ignoreTransformer(ignore))
+}
+
+// RankMatchFoldIgnoring is a case-insensitive version of RankMatchIgnoring.
+func RankMatchFoldIgnoring(source, target string, ignore runes.Set) int {
+	return rank(source, target, ignoreFoldTransformer(ignore))
+}
+
+// RankFindIgnoring is a version of RankFind that disregards the runes in
+// ignore. The Levenshtein distance is measured without the ignored runes,
+// while Source and Target of each Rank hold the original strings.
+func RankFindIgnoring(source string, targets []string, ignore runes.Set) Ranks {
+	return rankFind(source, targets, ignoreTransformer(ignore))
+}
+
+// RankFindFoldIgnoring is a case-insensitive version of RankFindIgnoring.
+func RankFindFoldIgnoring(source string, targets []string, ignore runes.Set) Ranks {
+	return rankFind(source, targets, ignoreFoldTransformer(ignore))
+}
+
+// MatchPositionsIgnoring is a version of MatchPositions that disregards the
+// runes in ignore. The offsets refer to the original target.
+func MatchPositionsIgnoring(source, target string, ignore runes.Set) []int {
+	return matchPositions(source, target, ignoreTransformer(ignore))
+}
+
+// MatchPositionsFoldIgnoring is a case-insensitive version of MatchPositionsIgnoring.
+func MatchPositionsFoldIgnoring(source, target string, ignore runes.Set) []int {
+	return matchPositions(source, target, ignoreFoldTransformer(ignore))
+}
@@ -0,0 +1,84 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var ignorePunctSpace = IgnoreAny(IgnorePunctuation, IgnoreSpace)
+
+func TestMatchIgnoring(t *testing.T) {
+	var ignoreTests = []struct {
+		source string
+		target string
+		wanted bool
+		fold   bool
+	}{
+		{"usa", "U.S.A.", false, true},
+		{"USA", "U.S.A.", true, true},
+		{"U.S.A.", "USA", true, true},
+		{"newyork", "New York", false, true},
+		{"New-York", "NewYork", true, true},
+		{"n.z.", "new jersey", false, false},
+	}
+
+	for _, val := range ignoreTests {
+		match := MatchIgnoring(val.source, val.target, ignorePunctSpace)
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+		match = MatchFoldIgnoring(val.source, val.target, ignorePunctSpace)
+		if match != val.fold {
+			t.Errorf("%s in %s expected folded match to be %t, got %t",
+				val.source, val.target, val.fold, match)
+		}
+	}
+}
+
+func TestFindIgnoring(t *testing.T) {
+	targets := []string{"U.S.A.", "U.K.", "us army", "Soviet Union"}
+
+	if matches, wanted := FindIgnoring("USA", targets, IgnorePunctuation), []string{"U.S.A."}; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+	if matches, wanted := FindFoldIgnoring("usa", targets, ignorePunctSpace), []string{"U.S.A.", "us army"}; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+}
+
+func TestRankIgnoring(t *testing.T) {
+	if rank := RankMatchIgnoring("newyork", "new-york", IgnorePunctuation); rank != 0 {
+		t.Errorf("expected ranking 0, got %d", rank)
+	}
+	if rank := RankMatchFoldIgnoring("usa", "U.S. Army", ignorePunctSpace); rank != 3 {
+		t.Errorf("expected ranking 3, got %d", rank)
+	}
+
+	ranks := RankFindFoldIgnoring("usa", []string{"U.S.A.", "U.S. Army", "UK"}, ignorePunctSpace)
+	wanted := Ranks{{"usa", "U.S.A.", 0, 0}, {"usa", "U.S. Army", 3, 1}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	ranks = RankFindIgnoring("a-b", []string{"a b", "a.b.c"}, IgnoreAny(IgnorePunctuation, IgnoreSpace, IgnoreSymbols))
+	wanted = Ranks{{"a-b", "a b", 0, 0}, {"a-b", "a.b.c", 1, 1}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func TestMatchPositionsIgnoring(t *testing.T) {
+	if positions, wanted := MatchPositionsIgnoring("NY", "New York", ignorePunctSpace), []int{0, 4}; !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+	if positions, wanted := MatchPositionsFoldIgnoring("usa", "U.S.A.", ignorePunctSpace), []int{0, 2, 4}; !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+}
+
+func ExampleMatchFoldIgnoring() {
+	fmt.Print(MatchFoldIgnoring("newyork", "New York", IgnoreSpace))
+	// Output: true
+}
@@ -0,0 +1,303 @@
+package fuzzy
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// An Index holds posting lists of the runes and trigrams of a list of
+// targets, so that searches only have to look at the targets that can
+// possibly match instead of scanning all of them. Every search returns exactly
+// the same result as the corresponding function taking the list of targets,
+// e.g. Index.Find(source) is the same as Find(source, targets). An Index is
+// immutable and safe for concurrent use.
+type Index struct {
+	targets     []string
+	transformed []string
+	lengths     []int // rune counts of transformed
+
+	// runes and trigrams map every rune and trigram to the ascending indices
+	// of the transformed targets containing it.
+	runes    map[rune][]int
+	trigrams map[string][]int
+
+	transformer func() transform.Transformer
+}
+
+// NewIndex builds an index of targets. The slice is retained by the index and
+// must not be modified afterwards.
+func NewIndex(targets []string) *Index {
+	return newIndex(targets, noopTransformer)
+}
+
+// NewIndexFold is a case-insensitive version of NewIndex. Searches compare
+// the case-folded source and targets, like FindFold and its siblings.
+func NewIndexFold(targets []string) *Index {
+	return newIndex(targets, foldTransformer)
+}
+
+func newIndex(targets []string, transformer func() transform.Transformer) *Index {
+	x := &Index{
+		targets:     targets,
+		transformed: make([]string, len(targets)),
+		lengths:     make([]int, len(targets)),
+		runes:       make(map[rune][]int),
+		trigrams:    make(map[string][]int),
+		transformer: transformer,
+	}
+
+	t := transformer()
+	for index, target := range targets {
+		targetT := stringTransform(target, t)
+		x.transformed[index] = targetT
+		x.lengths[index] = utf8.RuneCountInString(targetT)
+
+		for r := range runeSet(targetT) {
+			x.runes[r] = append(x.runes[r], index)
+		}
+		for g := range NGrams(targetT, 3) {
+			x.trigrams[g] = append(x.trigrams[g], index)
+		}
+	}
+
+	return x
+}
+
+// runeSet returns the distinct runes of s.
+func runeSet(s string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range s {
+		set[r] = true
+	}
+	return set
+}
+
+// Len returns the number of indexed targets.
+func (x *Index) Len() int {
+	return len(x.targets)
+}
+
+// Find is the same as Find, or FindFold for an index built by NewIndexFold,
+// with the indexed targets.
+func (x *Index) Find(source string) []string {
+	sourceT := stringTransform(source, x.transformer())
+
+	var matches []string
+
+	for _, index := range x.matchCandidates(sourceT) {
+		if matchTransformed(sourceT, x.transformed[index]) {
+			matches = append(matches, x.targets[index])
+		}
+	}
+
+	return matches
+}
+
+// RankFind is the same as RankFind, or RankFindFold for an index built by
+// NewIndexFold, with the indexed targets.
+func (x *Index) RankFind(source string) Ranks {
+	sourceT := stringTransform(source, x.transformer())
+
+	var r Ranks
+
+	for _, index := range x.matchCandidates(sourceT) {
+		targetT := x.transformed[index]
+		if matchTransformed(sourceT, targetT) {
+			distance := LevenshteinDistance(sourceT, targetT)
+			r = append(r, Rank{source, x.targets[index], distance, index})
+		}
+	}
+
+	return r
+}
+
+// RankWithin is the same as RankWithin, or RankWithinFold for an index built
+// by NewIndexFold, with the indexed targets.
+func (x *Index) RankWithin(source string, maxDistance int) Ranks {
+	sourceT := stringTransform(source, x.transformer())
+	length := utf8.RuneCountInString(sourceT)
+
+	var r Ranks
+
+	for _, index := range x.distanceCandidates(sourceT, maxDistance) {
+		if abs(x.lengths[index]-length) > maxDistance {
+			continue
+		}
+		if distance := LevenshteinDistance(sourceT, x.transformed[index]); distance <= maxDistance {
+			r = append(r, Rank{source, x.targets[index], distance, index})
+		}
+	}
+
+	return r
+}
+
+// RankFindNGram is the same as RankFindNGram, or RankFindNGramFold for an
+// index built by NewIndexFold, with the indexed targets and n = 3.
+func (x *Index) RankFindNGram(source string, measure NGramMeasure) MetricRanks {
+	p := NGrams(stringTransform(source, x.transformer()), 3)
+
+	// Two empty strings are equal, even though they share no trigrams.
+	var candidates []int
+	if len(p) == 0 {
+		for index, length := range x.lengths {
+			if length == 0 {
+				candidates = append(candidates, index)
+			}
+		}
+	} else {
+		for index := range x.trigramCounts(p) {
+			candidates = append(candidates, index)
+		}
+		sort.Ints(candidates)
+	}
+
+	var r MetricRanks
+
+	for _, index := range candidates {
+		q := NGrams(x.transformed[index], 3)
+		if sim := measure.similarity(p, q); sim > 0 {
+			r = append(r, MetricRank{source, x.targets[index], sim, index})
+		}
+	}
+
+	r.sort(NGramMetric(3, measure))
+
+	return r
+}
+
+// matchCandidates returns the ascending indices of the targets containing
+// every rune of the transformed source, which all targets it matches do.
+func (x *Index) matchCandidates(sourceT string) []int {
+	var lists [][]int
+	for r := range runeSet(sourceT) {
+		list, ok := x.runes[r]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	if len(lists) == 0 {
+		return x.all()
+	}
+
+	// Intersecting the shortest lists first keeps the intermediate
+	// results small.
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+
+	candidates := lists[0]
+	for _, list := range lists[1:] {
+		candidates = intersectSorted(candidates, list)
+		if len(candidates) == 0 {
+			return nil
+		}
+	}
+
+	return candidates
+}
+
+// distanceCandidates returns the ascending indices of the targets that may
+// be within maxDistance edits of the transformed source. Every edit changes
+// at most three of the padded trigrams of a string, so a target within k edits
+// has to share all but 3k of the distinct trigrams of the source. When that
+// bound is useless, all targets are candidates.
+func (x *Index) distanceCandidates(sourceT string, maxDistance int) []int {
+	if maxDistance < 0 {
+		return nil
+	}
+
+	p := NGrams(sourceT, 3)
+	threshold := len(p) - 3*maxDistance
+	if threshold <= 0 {
+		return x.all()
+	}
+
+	var candidates []int
+	for index, count := range x.trigramCounts(p) {
+		if count >= threshold {
+			candidates = append(candidates, index)
+		}
+	}
+	sort.Ints(candidates)
+
+	return candidates
+}
+
+// trigramCounts returns, for every target sharing a trigram with p, the
+// number of distinct trigrams they share.
+func (x *Index) trigramCounts(p NGramProfile) map[int]int {
+	counts := make(map[int]int)
+	for g := range p {
+		for _, index := range x.trigrams[g] {
+			counts[index]++
+		}
+	}
+	return counts
+}
+
+// all returns the indices of all targets.
+func (x *Index) all() []int {
+	indices := make([]int, len(x.targets))
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// intersectSorted returns the elements present in both ascending lists a and
+// b.
+func intersectSorted(a, b []int) []int {
+	var c []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			c = append(c, a[i])
+			i++
+			j++
+		}
+	}
+	return c
+}
+
+// RankWithin returns the targets within maxDistance edits of source, with
+// their Levenshtein distance, in the order of targets. Unlike RankFind, the
+// targets don't have to contain the runes of source in order, so it's useful
+// for tolerating typos. See Index.RankWithin for a faster version for large
+// lists of targets.
+func RankWithin(source string, targets []string, maxDistance int) Ranks {
+	return rankWithin(source, targets, maxDistance, noopTransformer())
+}
+
+// RankWithinFold is a case-insensitive version of RankWithin.
+func RankWithinFold(source string, targets []string, maxDistance int) Ranks {
+	return rankWithin(source, targets, maxDistance, foldTransformer())
+}
+
+func rankWithin(source string, targets []string, maxDistance int, transformer transform.Transformer) Ranks {
+	sourceT := stringTransform(source, transformer)
+
+	var r Ranks
+
+	for index, target := range targets {
+		targetT := stringTransform(target, transformer)
+		if distance := LevenshteinDistance(sourceT, targetT); distance <= maxDistance {
+			r = append(r, Rank{source, target, distance, index})
+		}
+	}
+
+	return r
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,110 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomWords returns n random words over a small alphabet, so that they
+// share many runes and trigrams.
+func randomWords(rng *rand.Rand, n int) []string {
+	const alphabet = "abcdeABCDÉé "
+	runes := []rune(alphabet)
+	words := make([]string, n)
+	for i := range words {
+		w := make([]rune, rng.Intn(9))
+		for j := range w {
+			w[j] = runes[rng.Intn(len(runes))]
+		}
+		words[i] = string(w)
+	}
+	return words
+}
+
+func TestIndexAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	targets := randomWords(rng, 500)
+	index, indexFold := NewIndex(targets), NewIndexFold(targets)
+
+	for _, source := range randomWords(rng, 200) {
+		if got, want := index.Find(source), Find(source, targets); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Find(%q): got %q, expected %q", source, got, want)
+		}
+		if got, want := indexFold.Find(source), FindFold(source, targets); !reflect.DeepEqual(got, want) {
+			t.Fatalf("FindFold(%q): got %q, expected %q", source, got, want)
+		}
+		if got, want := index.RankFind(source), RankFind(source, targets); !reflect.DeepEqual(got, want) {
+			t.Fatalf("RankFind(%q): got %v, expected %v", source, got, want)
+		}
+		if got, want := indexFold.RankFind(source), RankFindFold(source, targets); !reflect.DeepEqual(got, want) {
+			t.Fatalf("RankFindFold(%q): got %v, expected %v", source, got, want)
+		}
+		for k := 0; k <= 3; k++ {
+			if got, want := index.RankWithin(source, k), RankWithin(source, targets, k); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RankWithin(%q, %d): got %v, expected %v", source, k, got, want)
+			}
+			if got, want := indexFold.RankWithin(source, k), RankWithinFold(source, targets, k); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RankWithinFold(%q, %d): got %v, expected %v", source, k, got, want)
+			}
+		}
+		for _, measure := range []NGramMeasure{Jaccard, Dice, Cosine} {
+			if got, want := index.RankFindNGram(source, measure), RankFindNGram(source, targets, 3, measure); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RankFindNGram(%q): got %v, expected %v", source, got, want)
+			}
+			if got, want := indexFold.RankFindNGram(source, measure), RankFindNGramFold(source, targets, 3, measure); !reflect.DeepEqual(got, want) {
+				t.Fatalf("RankFindNGramFold(%q): got %v, expected %v", source, got, want)
+			}
+		}
+	}
+}
+
+func TestIndexCandidates(t *testing.T) {
+	index := NewIndex([]string{"cartwheel", "foobar", "wheel", "baz", "whale"})
+
+	if got, want := index.matchCandidates("whl"), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got candidates %v, expected %v", got, want)
+	}
+	if got := index.matchCandidates("xyz"); got != nil {
+		t.Errorf("expected no candidates, got %v", got)
+	}
+	if got, want := index.distanceCandidates("wheel", 1), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got candidates %v, expected %v", got, want)
+	}
+	if got := index.distanceCandidates("wheel", -1); got != nil {
+		t.Errorf("expected no candidates, got %v", got)
+	}
+	if index.Len() != 5 {
+		t.Errorf("expected 5 targets, got %d", index.Len())
+	}
+}
+
+func FuzzIndex(f *testing.F) {
+	f.Add("whl", "cartwheel wheel whale", 1)
+	f.Fuzz(func(t *testing.T, source string, corpus string, k int) {
+		targets := make([]string, 0, len(corpus))
+		for i := 0; i+2 <= len(corpus); i += 2 {
+			targets = append(targets, corpus[i:i+2])
+		}
+		targets = append(targets, corpus)
+		k %= 4
+
+		index := NewIndexFold(targets)
+		if got, want := index.RankFind(source), RankFindFold(source, targets); !reflect.DeepEqual(got, want) {
+			t.Fatalf("RankFindFold(%q): got %v, expected %v", source, got, want)
+		}
+		if got, want := index.RankWithin(source, k), RankWithinFold(source, targets, k); !reflect.DeepEqual(got, want) {
+			t.Fatalf("RankWithinFold(%q, %d): got %v, expected %v", source, k, got, want)
+		}
+	})
+}
+
+func ExampleIndex_RankWithin() {
+	index := NewIndex([]string{"cartwheel", "foobar", "wheel", "baz", "wheels"})
+	for _, r := range index.RankWithin("wehel", 2) {
+		fmt.Println(r.Target, r.Distance)
+	}
+	// Output:
+	// wheel 2
+}
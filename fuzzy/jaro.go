@@ -0,0 +1,83 @@
+package fuzzy
+
+// JaroSimilarity returns the Jaro similarity of s and t, a value in [0, 1]
+// where 1 means the strings are equal. It's based on the number of runes the
+// strings have in common within a window and the number of transpositions
+// among them, and works best for short strings like names.
+func JaroSimilarity(s, t string) float64 {
+	r1, r2 := []rune(s), []rune(t)
+	if len(r1) == 0 && len(r2) == 0 {
+		return 1
+	}
+	if len(r1) == 0 || len(r2) == 0 {
+		return 0
+	}
+
+	window := max2(len(r1), len(r2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1 := make([]bool, len(r1))
+	matched2 := make([]bool, len(r2))
+	matches := 0
+
+	for i, r := range r1 {
+		for j := max2(0, i-window); j < min2(len(r2), i+window+1); j++ {
+			if !matched2[j] && r2[j] == r {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i, r := range r1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if r != r2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(r1)) + m/float64(len(r2)) + (m-float64(transpositions/2))/m) / 3
+}
+
+// JaroWinklerSimilarity is a version of JaroSimilarity that favors strings
+// sharing a common prefix of up to four runes, using the standard scaling
+// factor of 0.1. The boost is only applied when the Jaro similarity exceeds
+// 0.7.
+func JaroWinklerSimilarity(s, t string) float64 {
+	const (
+		scaling   = 0.1
+		threshold = 0.7
+		maxPrefix = 4
+	)
+
+	sim := JaroSimilarity(s, t)
+	if sim <= threshold {
+		return sim
+	}
+
+	prefix := 0
+	r2 := []rune(t)
+	for _, r := range s {
+		if prefix == maxPrefix || prefix >= len(r2) || r2[prefix] != r {
+			break
+		}
+		prefix++
+	}
+
+	return sim + float64(prefix)*scaling*(1-sim)
+}
@@ -0,0 +1,37 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+)
+
+var jaroTests = []struct {
+	s, t    string
+	jaro    float64
+	winkler float64
+}{
+	{"", "", 1, 1},
+	{"a", "", 0, 0},
+	{"MARTHA", "MARHTA", 0.944444, 0.961111},
+	{"DIXON", "DICKSONX", 0.766667, 0.813333},
+	{"JELLYFISH", "SMELLYFISH", 0.896296, 0.896296},
+	{"CRATE", "TRACE", 0.733333, 0.733333},
+	{"abc", "xyz", 0, 0},
+	{"ёлка", "ёлочка", 0.888889, 0.911111},
+}
+
+func TestJaroSimilarity(t *testing.T) {
+	for _, test := range jaroTests {
+		if sim := JaroSimilarity(test.s, test.t); math.Abs(sim-test.jaro) > 1e-6 {
+			t.Errorf("got similarity %f, expected %f for %s and %s", sim, test.jaro, test.s, test.t)
+		}
+	}
+}
+
+func TestJaroWinklerSimilarity(t *testing.T) {
+	for _, test := range jaroTests {
+		if sim := JaroWinklerSimilarity(test.s, test.t); math.Abs(sim-test.winkler) > 1e-6 {
+			t.Errorf("got similarity %f, expected %f for %s and %s", sim, test.winkler, test.s, test.t)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LCSLength returns the length in runes of the longest common subsequence
+// of s and t, i.e. the longest sequence of runes that appear in both strings
+// in the same order, though not necessarily next to each other. It uses
+// O(min(len(s), len(t))) space.
+func LCSLength(s, t string) int {
+	r1, r2 := []rune(s), []rune(t)
+	if len(r2) > len(r1) {
+		r1, r2 = r2, r1
+	}
+	row := lcsRow(r1, r2)
+	return row[len(r2)]
+}
+
+// LCS returns the longest common subsequence of s and t, see LCSLength. If
+// there are several, the one returned is unspecified. It uses Hirschberg's
+// algorithm, which needs O(len(s) * len(t)) time but only linear space, so
+// it's suitable for long inputs.
+func LCS(s, t string) string {
+	r1, r2 := []rune(s), []rune(t)
+
+	var b strings.Builder
+	for _, p := range lcsPairs(r1, r2) {
+		b.WriteRune(r1[p[0]])
+	}
+	return b.String()
+}
+
+// LCSPositions returns the byte offsets in s and t of the runes of their
+// longest common subsequence, e.g. for highlighting the shared parts.
+func LCSPositions(s, t string) (sPositions, tPositions []int) {
+	pairs := lcsPairs([]rune(s), []rune(t))
+	offsets1, offsets2 := runeOffsets(s), runeOffsets(t)
+
+	sPositions = make([]int, len(pairs))
+	tPositions = make([]int, len(pairs))
+	for i, p := range pairs {
+		sPositions[i] = offsets1[p[0]]
+		tPositions[i] = offsets2[p[1]]
+	}
+	return sPositions, tPositions
+}
+
+// LCSSimilarity returns the similarity of s and t based on their longest
+// common subsequence, as 2*LCSLength(s, t)/(len(s)+len(t)) counting runes.
+// It's 1 for equal strings and 0 for strings without any runes in common.
+func LCSSimilarity(s, t string) float64 {
+	n := utf8.RuneCountInString(s) + utf8.RuneCountInString(t)
+	if n == 0 {
+		return 1
+	}
+	return 2 * float64(LCSLength(s, t)) / float64(n)
+}
+
+// lcsRow returns the last row of the LCS length table of a and b, i.e. the
+// LCS length of a and b[:j] for every j.
+func lcsRow(a, b []rune) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max2(cur[j], prev[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev
+}
+
+// lcsPairs returns the rune indices in a and b of a longest common
+// subsequence, using Hirschberg's algorithm.
+func lcsPairs(a, b []rune) [][2]int {
+	var pairs [][2]int
+	hirschberg(a, b, 0, 0, &pairs)
+	return pairs
+}
+
+func hirschberg(a, b []rune, offsetA, offsetB int, pairs *[][2]int) {
+	if len(a) == 0 || len(b) == 0 {
+		return
+	}
+	if len(a) == 1 {
+		for j, r := range b {
+			if r == a[0] {
+				*pairs = append(*pairs, [2]int{offsetA, offsetB + j})
+				break
+			}
+		}
+		return
+	}
+
+	mid := len(a) / 2
+	forward := lcsRow(a[:mid], b)
+	backward := lcsRow(reverseRunes(a[mid:]), reverseRunes(b))
+
+	// Split b where the two halves of a together share the most runes.
+	split, most := 0, -1
+	for j := 0; j <= len(b); j++ {
+		if n := forward[j] + backward[len(b)-j]; n > most {
+			split, most = j, n
+		}
+	}
+
+	hirschberg(a[:mid], b[:split], offsetA, offsetB, pairs)
+	hirschberg(a[mid:], b[split:], offsetA+mid, offsetB+split, pairs)
+}
+
+func reverseRunes(r []rune) []rune {
+	reversed := make([]rune, len(r))
+	for i, x := range r {
+		reversed[len(r)-1-i] = x
+	}
+	return reversed
+}
+
+// LongestCommonSubstring returns the longest string of consecutive runes
+// contained in both s and t, along with its byte offsets in s and t. If there
+// are several, the one ending first in s is returned. If s and t have no runes
+// in common, it returns an empty string and offsets of -1. It uses
+// O(len(s) * len(t)) time and O(len(t)) space, counting runes.
+func LongestCommonSubstring(s, t string) (substring string, sIndex, tIndex int) {
+	r1, r2 := []rune(s), []rune(t)
+	prev := make([]int, len(r2)+1)
+	cur := make([]int, len(r2)+1)
+
+	// Length and end rune indices of the longest common substring.
+	length, end1, end2 := 0, 0, 0
+
+	for i := range r1 {
+		for j := range r2 {
+			if r1[i] == r2[j] {
+				cur[j+1] = prev[j] + 1
+				if cur[j+1] > length {
+					length, end1, end2 = cur[j+1], i+1, j+1
+				}
+			} else {
+				cur[j+1] = 0
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	if length == 0 {
+		return "", -1, -1
+	}
+
+	sIndex = runeOffset(s, end1-length)
+	tIndex = runeOffset(t, end2-length)
+	return string(r1[end1-length : end1]), sIndex, tIndex
+}
@@ -0,0 +1,94 @@
+package fuzzy
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var lcsTests = []struct {
+	s, t   string
+	lcs    string
+	substr string
+}{
+	{"", "", "", ""},
+	{"abc", "", "", ""},
+	{"abc", "abc", "abc", "abc"},
+	{"ABCBDAB", "BDCABA", "BCBA", "AB"},
+	{"cartwheel", "wheelbarrow", "wheel", "wheel"},
+	{"kitten", "sitting", "ittn", "itt"},
+	{"ёлка", "ёлочка", "ёлка", "ёл"},
+	{"中华人民共和国", "中国人民", "中人民", "人民"},
+	{"abc", "xyz", "", ""},
+}
+
+func TestLCS(t *testing.T) {
+	for _, test := range lcsTests {
+		lcs := LCS(test.s, test.t)
+		if len([]rune(lcs)) != len([]rune(test.lcs)) || !Match(lcs, test.s) || !Match(lcs, test.t) {
+			t.Errorf("expected common subsequence like %q, got %q for %s and %s", test.lcs, lcs, test.s, test.t)
+		}
+		if n := LCSLength(test.s, test.t); n != len([]rune(test.lcs)) {
+			t.Errorf("expected length %d, got %d for %s and %s", len([]rune(test.lcs)), n, test.s, test.t)
+		}
+		if n := LCSLength(test.t, test.s); n != len([]rune(test.lcs)) {
+			t.Errorf("expected length %d, got %d for %s and %s", len([]rune(test.lcs)), n, test.t, test.s)
+		}
+	}
+}
+
+func TestLCSLong(t *testing.T) {
+	s := strings.Repeat(deBelloGallico, 3)
+	if n := LCSLength(s, deBelloGallico); n != len(deBelloGallico) {
+		t.Errorf("expected length %d, got %d", len(deBelloGallico), n)
+	}
+	if lcs := LCS(deBelloGallico, s); lcs != deBelloGallico {
+		t.Errorf("expected the whole text as subsequence")
+	}
+}
+
+func TestLCSPositions(t *testing.T) {
+	sPositions, tPositions := LCSPositions("ёлка", "ёлочка")
+	if !reflect.DeepEqual(sPositions, []int{0, 2, 4, 6}) || !reflect.DeepEqual(tPositions, []int{0, 2, 8, 10}) {
+		t.Errorf("unexpected positions %v %v", sPositions, tPositions)
+	}
+}
+
+func TestLCSSimilarity(t *testing.T) {
+	var similarityTests = []struct {
+		s, t   string
+		wanted float64
+	}{
+		{"", "", 1},
+		{"abc", "", 0},
+		{"abc", "abc", 1},
+		{"cartwheel", "wheelbarrow", 0.5},
+		{"abc", "xyz", 0},
+	}
+
+	for _, test := range similarityTests {
+		if sim := LCSSimilarity(test.s, test.t); math.Abs(sim-test.wanted) > 1e-9 {
+			t.Errorf("got similarity %v, expected %v for %s and %s", sim, test.wanted, test.s, test.t)
+		}
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	for _, test := range lcsTests {
+		substr, i, j := LongestCommonSubstring(test.s, test.t)
+		if substr != test.substr {
+			t.Errorf("expected %q, got %q for %s and %s", test.substr, substr, test.s, test.t)
+			continue
+		}
+		if substr == "" {
+			if i != -1 || j != -1 {
+				t.Errorf("expected offsets -1, got %d %d", i, j)
+			}
+			continue
+		}
+		if !strings.HasPrefix(test.s[i:], substr) || !strings.HasPrefix(test.t[j:], substr) {
+			t.Errorf("wrong offsets %d %d for %q in %s and %s", i, j, substr, test.s, test.t)
+		}
+	}
+}
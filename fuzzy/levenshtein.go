@@ -1,5 +1,7 @@
 package fuzzy
 
+import "unicode/utf8"
+
 // LevenshteinDistance measures the difference between two strings.
 // The Levenshtein distance between two words is the minimum number of
 // single-character edits (i.e. insertions, deletions or substitutions)
@@ -33,6 +35,88 @@ func LevenshteinDistance(s, t string) int {
 	return column[len(r1)]
 }
 
+// DamerauLevenshteinDistance is like LevenshteinDistance, but also counts
+// the transposition of two adjacent characters as a single edit, so "ab" and
+// "ba" are at distance 1 rather than 2. This is the unrestricted variant, where
+// a substring may be edited more than once, which unlike the simpler optimal
+// string alignment distance is a metric.
+func DamerauLevenshteinDistance(s, t string) int {
+	r1, r2 := []rune(s), []rune(t)
+	inf := len(r1) + len(r2)
+
+	// d is indexed from -1, and lastRow holds the last row where each rune
+	// of s was seen.
+	d := make([][]int, len(r1)+2)
+	for i := range d {
+		d[i] = make([]int, len(r2)+2)
+		d[i][0] = inf
+	}
+	for i := 0; i <= len(r1); i++ {
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(r2); j++ {
+		d[0][j+1] = inf
+		d[1][j+1] = j
+	}
+	lastRow := make(map[rune]int)
+
+	for i := 1; i <= len(r1); i++ {
+		lastCol := 0
+		for j := 1; j <= len(r2); j++ {
+			k, l := lastRow[r2[j-1]], lastCol
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+				lastCol = j
+			}
+			d[i+1][j+1] = min(d[i][j]+cost, d[i+1][j]+1, d[i][j+1]+1)
+			d[i+1][j+1] = min2(d[i+1][j+1], d[k][l]+(i-k-1)+1+(j-l-1))
+		}
+		lastRow[r1[i-1]] = i
+	}
+
+	return d[len(r1)+1][len(r2)+1]
+}
+
+// LevenshteinSimilarity returns the Levenshtein distance between s and t
+// normalized to a similarity in [0, 1], where 1 means the strings are equal.
+// It's computed as 1 - distance/max(len(s), len(t)), counting runes, which
+// makes it comparable between strings of different lengths. Two empty strings
+// have a similarity of 1.
+func LevenshteinSimilarity(s, t string) float64 {
+	return similarity(LevenshteinDistance(s, t), max2(utf8.RuneCountInString(s), utf8.RuneCountInString(t)))
+}
+
+// LevenshteinAlignmentSimilarity is like LevenshteinSimilarity, but
+// normalizes the distance by the length of the alignment of the two strings
+// rather than by the longest string. It's computed as
+// 1 - 2*distance/(len(s)+len(t)+distance), which unlike LevenshteinSimilarity
+// gives a proper metric when subtracted from 1, as shown by Yujian and Bo in
+// "A Normalized Levenshtein Distance Metric" (2007).
+func LevenshteinAlignmentSimilarity(s, t string) float64 {
+	d := LevenshteinDistance(s, t)
+	return similarity(2*d, utf8.RuneCountInString(s)+utf8.RuneCountInString(t)+d)
+}
+
+// similarity maps a distance in [0, length] to a similarity in [0, 1].
+func similarity(distance, length int) float64 {
+	if length == 0 {
+		return 1
+	}
+	s := 1 - float64(distance)/float64(length)
+	if s < 0 {
+		return 0
+	}
+	return s
+}
+
+func max2(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func min2(a, b int) int {
 	if a < b {
 		return a
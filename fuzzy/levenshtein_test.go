@@ -1,6 +1,9 @@
 package fuzzy
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 var levenshteinDistanceTests = []struct {
 	s, t   string
@@ -52,3 +55,61 @@ func BenchmarkLevenshteinDistanceBigEarly(b *testing.B) {
 		LevenshteinDistance(ldt.s, ldt.t)
 	}
 }
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	var similarityTests = []struct {
+		s, t      string
+		wanted    float64
+		alignment float64
+	}{
+		{"", "", 1, 1},
+		{"a", "", 0, 0},
+		{"a", "a", 1, 1},
+		{"ab", "aa", 0.5, 0.6},
+		{"kitten", "sitting", 1 - 3.0/7, 1 - 6.0/16},
+		{"ёлка", "ёлочка", 1 - 2.0/6, 1 - 4.0/12},
+		{"bbb", "a", 0, 1 - 6.0/7},
+	}
+
+	for _, test := range similarityTests {
+		if similarity := LevenshteinSimilarity(test.s, test.t); math.Abs(similarity-test.wanted) > 1e-9 {
+			t.Errorf("got similarity %v, expected %v for %s and %s",
+				similarity, test.wanted, test.s, test.t)
+		}
+		if similarity := LevenshteinAlignmentSimilarity(test.s, test.t); math.Abs(similarity-test.alignment) > 1e-9 {
+			t.Errorf("got alignment similarity %v, expected %v for %s and %s",
+				similarity, test.alignment, test.s, test.t)
+		}
+	}
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	var damerauTests = []struct {
+		s, t   string
+		wanted int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"ab", "ba", 1},
+		{"ca", "abc", 2}, // 3 with the optimal string alignment distance
+		{"kitten", "sitting", 3},
+		{"teh", "the", 1},
+		{"ёлка", "ёклa", 2},
+		{"中国", "国中", 1},
+	}
+
+	for _, test := range damerauTests {
+		distance := DamerauLevenshteinDistance(test.s, test.t)
+		if distance != test.wanted {
+			t.Errorf("got distance %d, expected %d for %s in %s",
+				distance, test.wanted, test.s, test.t)
+		}
+	}
+
+	for _, test := range levenshteinDistanceTests {
+		if distance := DamerauLevenshteinDistance(test.s, test.t); distance > test.wanted {
+			t.Errorf("got distance %d, expected at most %d for %s in %s",
+				distance, test.wanted, test.s, test.t)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package fuzzy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/transform"
+)
+
+// A Metric measures how close a target string is to a source string. It's
+// either a distance, where lower scores are better, or a similarity, where
+// higher scores are better.
+type Metric interface {
+	// Score returns the distance or similarity between source and target.
+	Score(source, target string) float64
+
+	// HigherIsBetter reports whether the metric is a similarity.
+	HigherIsBetter() bool
+}
+
+// DistanceFunc adapts a distance function, like LevenshteinDistance, to a
+// Metric where lower scores are better.
+type DistanceFunc func(source, target string) int
+
+// Score returns f(source, target).
+func (f DistanceFunc) Score(source, target string) float64 {
+	return float64(f(source, target))
+}
+
+// HigherIsBetter returns false.
+func (f DistanceFunc) HigherIsBetter() bool {
+	return false
+}
+
+// SimilarityFunc adapts a similarity function, like JaroWinklerSimilarity, to
+// a Metric where higher scores are better.
+type SimilarityFunc func(source, target string) float64
+
+// Score returns f(source, target).
+func (f SimilarityFunc) Score(source, target string) float64 {
+	return f(source, target)
+}
+
+// HigherIsBetter returns true.
+func (f SimilarityFunc) HigherIsBetter() bool {
+	return true
+}
+
+// Metrics included in the package, which are also registered under their
+// names in lowercase and separated by hyphens, e.g. "jaro-winkler".
+var (
+	Levenshtein        Metric = DistanceFunc(LevenshteinDistance)
+	DamerauLevenshtein Metric = DistanceFunc(DamerauLevenshteinDistance)
+	Jaro               Metric = SimilarityFunc(JaroSimilarity)
+	JaroWinkler        Metric = SimilarityFunc(JaroWinklerSimilarity)
+
+	// LongestCommonSubsequence is registered as "lcs".
+	LongestCommonSubsequence Metric = SimilarityFunc(LCSSimilarity)
+)
+
+var (
+	metricsMu sync.RWMutex
+	metrics   = map[string]Metric{
+		"levenshtein":         Levenshtein,
+		"damerau-levenshtein": DamerauLevenshtein,
+		"jaro":                Jaro,
+		"jaro-winkler":        JaroWinkler,
+		"lcs":                 LongestCommonSubsequence,
+		"trigram":             NGramMetric(3, Jaccard),
+	}
+)
+
+// RegisterMetric makes a metric available by the provided name, e.g. for
+// selecting it from a configuration file. If RegisterMetric is called twice
+// with the same name or if metric is nil, it panics.
+func RegisterMetric(name string, metric Metric) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if metric == nil {
+		panic("fuzzy: RegisterMetric metric is nil")
+	}
+	if _, dup := metrics[name]; dup {
+		panic(fmt.Sprintf("fuzzy: RegisterMetric called twice for metric %q", name))
+	}
+	metrics[name] = metric
+}
+
+// LookupMetric returns the metric registered under name.
+func LookupMetric(name string) (Metric, bool) {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+
+	metric, ok := metrics[name]
+	return metric, ok
+}
+
+// MetricNames returns a sorted list of the names of the registered metrics.
+func MetricNames() []string {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RankFindMetric is similar to RankFind, except the matches are scored using
+// metric and returned sorted, best match first. Matches with equal scores
+// keep their order in targets.
+func RankFindMetric(source string, targets []string, metric Metric) MetricRanks {
+	return rankFindMetric(source, targets, metric, noopTransformer())
+}
+
+// RankFindMetricFold is a case-insensitive version of RankFindMetric. The
+// score is measured between the case-folded strings.
+func RankFindMetricFold(source string, targets []string, metric Metric) MetricRanks {
+	return rankFindMetric(source, targets, metric, foldTransformer())
+}
+
+// RankFindMetricTransform is a version of RankFindMetric that applies t to
+// source and all targets before matching and scoring. See MatchTransform.
+func RankFindMetricTransform(source string, targets []string, metric Metric, t transform.Transformer) MetricRanks {
+	return rankFindMetric(source, targets, metric, t)
+}
+
+// RankMetric is similar to RankFindMetric, except all targets are scored and
+// returned, whether they match source or not. This is useful for metrics
+// that tolerate typos, like JaroWinkler or local alignment scores.
+func RankMetric(source string, targets []string, metric Metric) MetricRanks {
+	r := make(MetricRanks, len(targets))
+	for index, target := range targets {
+		r[index] = MetricRank{source, target, metric.Score(source, target), index}
+	}
+	r.sort(metric)
+	return r
+}
+
+func rankFindMetric(source string, targets []string, metric Metric, transformer transform.Transformer) MetricRanks {
+	sourceT := stringTransform(source, transformer)
+
+	var r MetricRanks
+
+	for index, target := range targets {
+		targetT := stringTransform(target, transformer)
+		if matchTransformed(sourceT, targetT) {
+			r = append(r, MetricRank{source, target, metric.Score(sourceT, targetT), index})
+		}
+	}
+
+	r.sort(metric)
+
+	return r
+}
+
+type MetricRank struct {
+	// Source is used as the source for matching.
+	Source string
+
+	// Target is the word matched against.
+	Target string
+
+	// Score is the distance or similarity between Source and Target,
+	// according to the metric used.
+	Score float64
+
+	// Location of Target in original list
+	OriginalIndex int
+}
+
+type MetricRanks []MetricRank
+
+func (r MetricRanks) Len() int {
+	return len(r)
+}
+
+func (r MetricRanks) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+// Less orders the ranks by ascending score, which is best first for a
+// distance. For a similarity, use sort.Reverse.
+func (r MetricRanks) Less(i, j int) bool {
+	return r[i].Score < r[j].Score
+}
+
+// sort orders r best first according to metric, keeping the order of ranks
+// with equal scores.
+func (r MetricRanks) sort(metric Metric) {
+	if metric.HigherIsBetter() {
+		sort.Stable(sort.Reverse(r))
+	} else {
+		sort.Stable(r)
+	}
+}
@@ -0,0 +1,86 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRankFindMetric(t *testing.T) {
+	targets := []string{"cartwheel", "foobar", "wheel", "baz", "whale"}
+
+	ranks := RankFindMetric("whl", targets, Levenshtein)
+	wanted := MetricRanks{{"whl", "wheel", 2, 2}, {"whl", "whale", 2, 4}, {"whl", "cartwheel", 6, 0}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	ranks = RankFindMetric("whl", targets, JaroWinkler)
+	if len(ranks) != 3 || ranks[2].Target != "cartwheel" || ranks[0].Score < ranks[1].Score {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+}
+
+func TestRankFindMetricFold(t *testing.T) {
+	ranks := RankFindMetricFold("WHL", []string{"Wheel", "CartWheel"}, DamerauLevenshtein)
+	wanted := MetricRanks{{"WHL", "Wheel", 2, 0}, {"WHL", "CartWheel", 6, 1}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func TestSortingMetricRanks(t *testing.T) {
+	rs := MetricRanks{{"a", "b", 0.5, 0}, {"a", "cc", 0.25, 1}, {"a", "a", 1, 2}}
+	wanted := MetricRanks{rs[1], rs[0], rs[2]}
+
+	sort.Sort(rs)
+	if !reflect.DeepEqual(rs, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, rs)
+	}
+
+	sort.Sort(sort.Reverse(rs))
+	wanted = MetricRanks{wanted[2], wanted[1], wanted[0]}
+	if !reflect.DeepEqual(rs, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, rs)
+	}
+}
+
+func TestRegisterMetric(t *testing.T) {
+	name := "test-length-difference"
+	RegisterMetric(name, DistanceFunc(func(source, target string) int {
+		return len(target) - len(source)
+	}))
+
+	m, ok := LookupMetric(name)
+	if !ok {
+		t.Fatalf("metric %q not registered", name)
+	}
+	if score := m.Score("a", "abc"); score != 2 || m.HigherIsBetter() {
+		t.Errorf("unexpected metric %v %v", score, m.HigherIsBetter())
+	}
+	if names := MetricNames(); !sort.StringsAreSorted(names) || !strings.Contains(strings.Join(names, " "), name) {
+		t.Errorf("unexpected metric names %v", names)
+	}
+	if _, ok := LookupMetric("nonexistent"); ok {
+		t.Errorf("expected nonexistent metric to be missing")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected RegisterMetric to panic on duplicate")
+		}
+	}()
+	RegisterMetric("levenshtein", Levenshtein)
+}
+
+func ExampleRankFindMetric() {
+	ranks := RankFindMetric("whl", []string{"cartwheel", "foobar", "wheel", "baz"}, JaroWinkler)
+	for _, r := range ranks {
+		fmt.Printf("%s %.3f\n", r.Target, r.Score)
+	}
+	// Output:
+	// wheel 0.689
+	// cartwheel 0.000
+}
@@ -0,0 +1,172 @@
+package fuzzy
+
+import "strings"
+
+// DefaultGlobalScoring is a reasonable AlignmentScoring for comparing short
+// identifiers as a whole.
+var DefaultGlobalScoring = AlignmentScoring{Match: 1, Mismatch: -1, GapOpen: -2, GapExtend: -1}
+
+// AlignmentPair is a column of an alignment.
+type AlignmentPair struct {
+	// Source and Target are the byte offsets of the aligned runes in source
+	// and target, or -1 if the rune is aligned with a gap.
+	Source, Target int
+}
+
+// GlobalAlignment is the result of NeedlemanWunsch.
+type GlobalAlignment struct {
+	// Score is the score of the alignment.
+	Score int
+
+	// Pairs are the columns of the alignment, from left to right.
+	Pairs []AlignmentPair
+}
+
+// Format returns source and target as aligned strings of equal length in
+// runes, using gap to fill in the gaps.
+func (a GlobalAlignment) Format(source, target string, gap rune) (string, string) {
+	var b1, b2 strings.Builder
+	for _, p := range a.Pairs {
+		writeAligned(&b1, source, p.Source, gap)
+		writeAligned(&b2, target, p.Target, gap)
+	}
+	return b1.String(), b2.String()
+}
+
+func writeAligned(b *strings.Builder, s string, offset int, gap rune) {
+	if offset < 0 {
+		b.WriteRune(gap)
+		return
+	}
+	for _, r := range s[offset:] {
+		b.WriteRune(r)
+		return
+	}
+}
+
+// Alignment states, i.e. what the last column of an alignment holds.
+const (
+	alignPair      = iota // a rune from both strings
+	alignGapTarget        // a rune from source and a gap
+	alignGapSource        // a gap and a rune from target
+)
+
+// NeedlemanWunsch finds the best global alignment of source and target,
+// using the Needleman-Wunsch algorithm with affine gap scores as described by
+// Gotoh. Compared to LevenshteinDistance, it can express that opening a gap
+// should cost more than extending it, and that some substitutions are more
+// likely than others. It runs in O(len(source) * len(target)) time and space,
+// counting runes.
+func NeedlemanWunsch(source, target string, scoring AlignmentScoring) GlobalAlignment {
+	const negInf = -int(^uint(0)>>1) / 2
+	r1, r2 := []rune(source), []rune(target)
+	n, m := len(r1), len(r2)
+
+	// score[k][i][j] is the best score of aligning r1[:i] with r2[:j] where
+	// the last column is in state k, and from[k][i][j] is the state of the
+	// column before it.
+	var score, from [3][][]int
+	for k := range score {
+		score[k] = make([][]int, n+1)
+		from[k] = make([][]int, n+1)
+		for i := range score[k] {
+			score[k][i] = make([]int, m+1)
+			from[k][i] = make([]int, m+1)
+			for j := range score[k][i] {
+				score[k][i][j] = negInf
+			}
+		}
+	}
+	score[alignPair][0][0] = 0
+
+	// best returns the best of extending each state with a column scoring
+	// open, or extend if the state is the same as the column's.
+	best := func(i, j, state, open, extend int) (int, int) {
+		s, f := negInf, alignPair
+		for k := range score {
+			add := open
+			if k == state {
+				add = extend
+			}
+			if score[k][i][j] > negInf && score[k][i][j]+add > s {
+				s, f = score[k][i][j]+add, k
+			}
+		}
+		return s, f
+	}
+
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			if i > 0 && j > 0 {
+				sub := scoring.substitution(r1[i-1], r2[j-1])
+				score[alignPair][i][j], from[alignPair][i][j] = best(i-1, j-1, -1, sub, sub)
+			}
+			if i > 0 {
+				score[alignGapTarget][i][j], from[alignGapTarget][i][j] = best(i-1, j, alignGapTarget, scoring.GapOpen, scoring.GapExtend)
+			}
+			if j > 0 {
+				score[alignGapSource][i][j], from[alignGapSource][i][j] = best(i, j-1, alignGapSource, scoring.GapOpen, scoring.GapExtend)
+			}
+		}
+	}
+
+	state := alignPair
+	for k := range score {
+		if score[k][n][m] > score[state][n][m] {
+			state = k
+		}
+	}
+	a := GlobalAlignment{Score: score[state][n][m]}
+
+	// Trace back the alignment, collecting rune indices.
+	pairs := make([]AlignmentPair, 0, max2(n, m))
+	for i, j := n, m; i > 0 || j > 0; {
+		p := AlignmentPair{-1, -1}
+		prev := from[state][i][j]
+		switch state {
+		case alignPair:
+			i, j = i-1, j-1
+			p = AlignmentPair{i, j}
+		case alignGapTarget:
+			i--
+			p.Source = i
+		case alignGapSource:
+			j--
+			p.Target = j
+		}
+		pairs = append(pairs, p)
+		state = prev
+	}
+
+	// Reverse and convert rune indices to byte offsets.
+	offsets1, offsets2 := runeOffsets(source), runeOffsets(target)
+	a.Pairs = make([]AlignmentPair, len(pairs))
+	for k, p := range pairs {
+		if p.Source >= 0 {
+			p.Source = offsets1[p.Source]
+		}
+		if p.Target >= 0 {
+			p.Target = offsets2[p.Target]
+		}
+		a.Pairs[len(pairs)-1-k] = p
+	}
+
+	return a
+}
+
+// runeOffsets returns the byte offset of every rune in s.
+func runeOffsets(s string) []int {
+	offsets := make([]int, 0, len(s))
+	for i := range s {
+		offsets = append(offsets, i)
+	}
+	return offsets
+}
+
+// GlobalMetric returns a Metric scoring strings by the score of their best
+// global alignment, see NeedlemanWunsch.
+func (s AlignmentScoring) GlobalMetric() Metric {
+	return SimilarityFunc(func(source, target string) float64 {
+		return float64(NeedlemanWunsch(source, target, s).Score)
+	})
+}
@@ -0,0 +1,104 @@
+package fuzzy
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNeedlemanWunsch(t *testing.T) {
+	var nwTests = []struct {
+		source, target string
+		scoring        AlignmentScoring
+		score          int
+		s1, s2         string
+	}{
+		{
+			"GATTACA", "GCATGCU",
+			AlignmentScoring{Match: 1, Mismatch: -1, GapOpen: -1, GapExtend: -1},
+			0, "G-ATTACA", "GCA-TGCU",
+		},
+		{
+			"abcdefgh", "abcdxxxxefgh",
+			AlignmentScoring{Match: 1, Mismatch: -1, GapOpen: -1, GapExtend: -1},
+			4, "abcd----efgh", "abcdxxxxefgh",
+		},
+		{
+			"abcdefgh", "abcdxxxxefgh",
+			DefaultGlobalScoring,
+			3, "abcd----efgh", "abcdxxxxefgh",
+		},
+		{
+			"", "abc",
+			DefaultGlobalScoring,
+			-4, "---", "abc",
+		},
+		{
+			"", "",
+			DefaultGlobalScoring,
+			0, "", "",
+		},
+		{
+			"ёлка", "ёлочка",
+			DefaultGlobalScoring,
+			1, "ёл--ка", "ёлочка",
+		},
+	}
+
+	for _, test := range nwTests {
+		a := NeedlemanWunsch(test.source, test.target, test.scoring)
+		s1, s2 := a.Format(test.source, test.target, '-')
+		if a.Score != test.score || s1 != test.s1 || s2 != test.s2 {
+			t.Errorf("expected %d %q %q, got %d %q %q for %s and %s",
+				test.score, test.s1, test.s2, a.Score, s1, s2, test.source, test.target)
+		}
+	}
+}
+
+func TestNeedlemanWunschAffineGap(t *testing.T) {
+	// With linear gap scores, splitting the gap is as good as keeping it in
+	// one piece, while affine gap scores prefer a single gap.
+	source, target := "AAAC", "AAAXXC"
+	scoring := AlignmentScoring{Match: 2, Mismatch: -3, GapOpen: -4, GapExtend: -1}
+
+	a := NeedlemanWunsch(source, target, scoring)
+	s1, _ := a.Format(source, target, '-')
+	if a.Score != 3 || s1 != "AAA--C" {
+		t.Errorf("expected 3 %q, got %d %q", "AAA--C", a.Score, s1)
+	}
+}
+
+func TestNeedlemanWunschSubstitution(t *testing.T) {
+	ocr := NewEquivalence(OCRConfusions...)
+	scoring := DefaultGlobalScoring
+	scoring.Substitution = SubstitutionFunc(func(a, b rune) int {
+		switch {
+		case a == b:
+			return 2
+		case ocr.lookup(a) == ocr.lookup(b):
+			return 1
+		}
+		return -2
+	})
+
+	if a := NeedlemanWunsch("P0L-10S", "POL-IOS", scoring); a.Score != 11 {
+		t.Errorf("expected score 11, got %+v", a)
+	}
+
+	a := NeedlemanWunsch("ab", "xaxb", scoring)
+	wanted := []AlignmentPair{{-1, 0}, {0, 1}, {-1, 2}, {1, 3}}
+	if fmt.Sprint(a.Pairs) != fmt.Sprint(wanted) {
+		t.Errorf("expected pairs %v, got %v", wanted, a.Pairs)
+	}
+}
+
+func ExampleNeedlemanWunsch() {
+	a := NeedlemanWunsch("AB1234", "AB-12-34", DefaultGlobalScoring)
+	s1, s2 := a.Format("AB1234", "AB-12-34", '_')
+	fmt.Println(a.Score)
+	fmt.Println(s1)
+	fmt.Println(s2)
+	// Output:
+	// 2
+	// AB_12_34
+	// AB-12-34
+}
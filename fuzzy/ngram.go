@@ -0,0 +1,212 @@
+package fuzzy
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// nGramStart and nGramEnd pad strings before extracting n-grams, so that the
+// first and last runes are part of as many n-grams as the others. They are
+// bytes that never occur in UTF-8, so they can't be confused with the runes
+// of a string, which NGrams encodes as UTF-8.
+const (
+	nGramStart = "\xfe"
+	nGramEnd   = "\xff"
+)
+
+// NGramProfile holds the number of occurrences of each n-gram of a string.
+type NGramProfile map[string]int
+
+// NGrams returns the profile of the n-grams of s, i.e. all substrings of n
+// runes. The string is padded with n-1 start markers and n-1 end markers, the
+// bytes 0xFE and 0xFF, which never occur in UTF-8, so that e.g. the trigrams
+// of "ab" are "\xfe\xfea", "\xfeab", "ab\xff" and "b\xff\xff". Invalid UTF-8
+// in s is replaced by U+FFFD. An empty string has no n-grams. NGrams panics
+// if n < 1.
+func NGrams(s string, n int) NGramProfile {
+	if n < 1 {
+		panic("fuzzy: NGrams called with n < 1")
+	}
+
+	p := make(NGramProfile)
+	if s == "" {
+		return p
+	}
+
+	// Every n-gram is n consecutive symbols, each a marker or a rune.
+	symbols := make([]string, 0, utf8.RuneCountInString(s)+2*(n-1))
+	for i := 0; i < n-1; i++ {
+		symbols = append(symbols, nGramStart)
+	}
+	for _, r := range s {
+		symbols = append(symbols, string(r))
+	}
+	for i := 0; i < n-1; i++ {
+		symbols = append(symbols, nGramEnd)
+	}
+	for i := 0; i+n <= len(symbols); i++ {
+		p[strings.Join(symbols[i:i+n], "")]++
+	}
+
+	return p
+}
+
+// intersection returns the sum of the counts that p and q have in common, and
+// the total counts of p and q.
+func (p NGramProfile) intersection(q NGramProfile) (common, pTotal, qTotal int) {
+	for g, c := range p {
+		common += min2(c, q[g])
+		pTotal += c
+	}
+	for _, c := range q {
+		qTotal += c
+	}
+	return common, pTotal, qTotal
+}
+
+// Jaccard returns the Jaccard similarity of p and q, the size of their
+// intersection divided by the size of their union, in [0, 1]. Two empty
+// profiles have a similarity of 1.
+func (p NGramProfile) Jaccard(q NGramProfile) float64 {
+	common, pTotal, qTotal := p.intersection(q)
+	if pTotal+qTotal == 0 {
+		return 1
+	}
+	return float64(common) / float64(pTotal+qTotal-common)
+}
+
+// Dice returns the Sørensen-Dice coefficient of p and q, twice the size of
+// their intersection divided by their total size, in [0, 1]. Two empty
+// profiles have a similarity of 1.
+func (p NGramProfile) Dice(q NGramProfile) float64 {
+	common, pTotal, qTotal := p.intersection(q)
+	if pTotal+qTotal == 0 {
+		return 1
+	}
+	return 2 * float64(common) / float64(pTotal+qTotal)
+}
+
+// Cosine returns the cosine similarity of p and q seen as vectors of n-gram
+// counts, in [0, 1]. Two empty profiles have a similarity of 1.
+func (p NGramProfile) Cosine(q NGramProfile) float64 {
+	if len(p) == 0 && len(q) == 0 {
+		return 1
+	}
+
+	var dot, pNorm, qNorm float64
+	for g, c := range p {
+		dot += float64(c * q[g])
+		pNorm += float64(c * c)
+	}
+	for _, c := range q {
+		qNorm += float64(c * c)
+	}
+	if pNorm == 0 || qNorm == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(pNorm*qNorm)
+}
+
+// NGramMeasure selects how n-gram profiles are compared.
+type NGramMeasure int
+
+const (
+	// Jaccard selects NGramProfile.Jaccard.
+	Jaccard NGramMeasure = iota
+
+	// Dice selects NGramProfile.Dice.
+	Dice
+
+	// Cosine selects NGramProfile.Cosine.
+	Cosine
+)
+
+func (m NGramMeasure) similarity(p, q NGramProfile) float64 {
+	switch m {
+	case Dice:
+		return p.Dice(q)
+	case Cosine:
+		return p.Cosine(q)
+	default:
+		return p.Jaccard(q)
+	}
+}
+
+// NGramSimilarity returns the similarity of the n-gram profiles of s and t
+// using measure. For long, noisy strings like addresses, it's often a better
+// indicator than LevenshteinDistance since it doesn't depend on the order of
+// the parts.
+func NGramSimilarity(s, t string, n int, measure NGramMeasure) float64 {
+	return nGramSimilarity(s, t, n, measure, noopTransformer())
+}
+
+// NGramSimilarityFold is a case-insensitive version of NGramSimilarity.
+func NGramSimilarityFold(s, t string, n int, measure NGramMeasure) float64 {
+	return nGramSimilarity(s, t, n, measure, foldTransformer())
+}
+
+// NGramSimilarityNormalized is a unicode-normalized version of NGramSimilarity.
+func NGramSimilarityNormalized(s, t string, n int, measure NGramMeasure) float64 {
+	return nGramSimilarity(s, t, n, measure, normalizeTransformer())
+}
+
+// NGramSimilarityNormalizedFold is a unicode-normalized and case-insensitive version of NGramSimilarity.
+func NGramSimilarityNormalizedFold(s, t string, n int, measure NGramMeasure) float64 {
+	return nGramSimilarity(s, t, n, measure, normalizedFoldTransformer())
+}
+
+func nGramSimilarity(s, t string, n int, measure NGramMeasure, transformer transform.Transformer) float64 {
+	p := NGrams(stringTransform(s, transformer), n)
+	q := NGrams(stringTransform(t, transformer), n)
+	return measure.similarity(p, q)
+}
+
+// NGramMetric returns a Metric scoring strings by the similarity of their
+// n-gram profiles. The trigram Jaccard similarity is registered as "trigram".
+func NGramMetric(n int, measure NGramMeasure) Metric {
+	return SimilarityFunc(func(source, target string) float64 {
+		return NGramSimilarity(source, target, n, measure)
+	})
+}
+
+// RankFindNGram is similar to RankFind, except targets are found when they
+// share at least one n-gram with source, and ranked by the similarity of
+// their n-gram profiles. The result is sorted, most similar first.
+func RankFindNGram(source string, targets []string, n int, measure NGramMeasure) MetricRanks {
+	return rankFindNGram(source, targets, n, measure, noopTransformer())
+}
+
+// RankFindNGramFold is a case-insensitive version of RankFindNGram.
+func RankFindNGramFold(source string, targets []string, n int, measure NGramMeasure) MetricRanks {
+	return rankFindNGram(source, targets, n, measure, foldTransformer())
+}
+
+// RankFindNGramNormalized is a unicode-normalized version of RankFindNGram.
+func RankFindNGramNormalized(source string, targets []string, n int, measure NGramMeasure) MetricRanks {
+	return rankFindNGram(source, targets, n, measure, normalizeTransformer())
+}
+
+// RankFindNGramNormalizedFold is a unicode-normalized and case-insensitive version of RankFindNGram.
+func RankFindNGramNormalizedFold(source string, targets []string, n int, measure NGramMeasure) MetricRanks {
+	return rankFindNGram(source, targets, n, measure, normalizedFoldTransformer())
+}
+
+func rankFindNGram(source string, targets []string, n int, measure NGramMeasure, transformer transform.Transformer) MetricRanks {
+	p := NGrams(stringTransform(source, transformer), n)
+
+	var r MetricRanks
+
+	for index, target := range targets {
+		q := NGrams(stringTransform(target, transformer), n)
+		if sim := measure.similarity(p, q); sim > 0 {
+			r = append(r, MetricRank{source, target, sim, index})
+		}
+	}
+
+	r.sort(NGramMetric(n, measure))
+
+	return r
+}
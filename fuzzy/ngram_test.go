@@ -0,0 +1,113 @@
+package fuzzy
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNGrams(t *testing.T) {
+	var nGramTests = []struct {
+		s      string
+		n      int
+		wanted NGramProfile
+	}{
+		{"", 3, NGramProfile{}},
+		{"ab", 1, NGramProfile{"a": 1, "b": 1}},
+		{"ab", 2, NGramProfile{"\xfea": 1, "ab": 1, "b\xff": 1}},
+		{"ab", 3, NGramProfile{"\xfe\xfea": 1, "\xfeab": 1, "ab\xff": 1, "b\xff\xff": 1}},
+		{"aaa", 2, NGramProfile{"\xfea": 1, "aa": 2, "a\xff": 1}},
+		{"ёл", 2, NGramProfile{"\xfeё": 1, "ёл": 1, "л\xff": 1}},
+		{"\x00", 2, NGramProfile{"\xfe\x00": 1, "\x00\xff": 1}},
+		{"a\xff", 2, NGramProfile{"\xfea": 1, "a\uFFFD": 1, "\uFFFD\xff": 1}},
+	}
+
+	for _, test := range nGramTests {
+		if p := NGrams(test.s, test.n); !reflect.DeepEqual(p, test.wanted) {
+			t.Errorf("expected %q, got %q for %s", test.wanted, p, test.s)
+		}
+	}
+}
+
+func TestNGramProfileSimilarity(t *testing.T) {
+	var similarityTests = []struct {
+		s, t                  string
+		jaccard, dice, cosine float64
+	}{
+		{"", "", 1, 1, 1},
+		{"abc", "", 0, 0, 0},
+		{"abc", "abc", 1, 1, 1},
+		{"abc", "xyz", 0, 0, 0},
+		// Bigrams {^a ab bc c$} and {^a ab b$}, with ^ and $ the markers.
+		{"abc", "ab", 2.0 / 5, 4.0 / 7, 2 / math.Sqrt(12)},
+		// Bigrams {^a:1 aa:2 a$:1} and {^a aa a$}.
+		{"aaa", "aa", 3.0 / 4, 6.0 / 7, 4 / math.Sqrt(18)},
+		// NUL isn't confused with the markers: {^\x00 \x00\x00 \x00$} and
+		// {^\x00 \x00$}.
+		{"\x00\x00", "\x00", 2.0 / 3, 4.0 / 5, 2 / math.Sqrt(6)},
+	}
+
+	for _, test := range similarityTests {
+		p, q := NGrams(test.s, 2), NGrams(test.t, 2)
+		if sim := p.Jaccard(q); math.Abs(sim-test.jaccard) > 1e-9 {
+			t.Errorf("got Jaccard similarity %v, expected %v for %s and %s", sim, test.jaccard, test.s, test.t)
+		}
+		if sim := p.Dice(q); math.Abs(sim-test.dice) > 1e-9 {
+			t.Errorf("got Dice similarity %v, expected %v for %s and %s", sim, test.dice, test.s, test.t)
+		}
+		if sim := p.Cosine(q); math.Abs(sim-test.cosine) > 1e-9 {
+			t.Errorf("got cosine similarity %v, expected %v for %s and %s", sim, test.cosine, test.s, test.t)
+		}
+	}
+}
+
+func TestNGramSimilarityTransformed(t *testing.T) {
+	if sim := NGramSimilarity("Main Street", "main street", 3, Dice); sim == 1 {
+		t.Errorf("expected case-sensitive similarity below 1")
+	}
+	if sim := NGramSimilarityFold("Main Street", "main street", 3, Dice); sim != 1 {
+		t.Errorf("expected similarity 1, got %v", sim)
+	}
+	if sim := NGramSimilarityNormalized("Hauptstraße", "Hauptstraße", 3, Jaccard); sim != 1 {
+		t.Errorf("expected similarity 1, got %v", sim)
+	}
+	if sim := NGramSimilarityNormalizedFold("Rue de l'Église", "rue de l'eglise", 3, Cosine); math.Abs(sim-1) > 1e-9 {
+		t.Errorf("expected similarity 1, got %v", sim)
+	}
+}
+
+func TestRankFindNGram(t *testing.T) {
+	addresses := []string{
+		"12 Main Street, Springfield",
+		"Springfield, Main St. 12",
+		"Elm Street 5, Shelbyville",
+		"Oak Avenue",
+	}
+
+	ranks := RankFindNGramFold("main street 12 springfield", addresses, 3, Jaccard)
+	if len(ranks) != 3 || ranks[0].OriginalIndex != 0 || ranks[1].OriginalIndex != 1 {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+	for i := 1; i < len(ranks); i++ {
+		if ranks[i].Score > ranks[i-1].Score {
+			t.Errorf("ranks not sorted %+v", ranks)
+		}
+	}
+
+	if ranks := RankFindNGram("xyz", addresses, 3, Dice); ranks != nil {
+		t.Errorf("expected no ranks, got %+v", ranks)
+	}
+	if ranks := RankFindNGramNormalized("Avenue", addresses, 2, Cosine); len(ranks) == 0 || ranks[0].Target != "Oak Avenue" {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+	if ranks := RankFindNGramNormalizedFold("ÉLM", addresses, 2, Cosine); len(ranks) == 0 || ranks[0].OriginalIndex != 2 {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+}
+
+func TestNGramMetricRegistered(t *testing.T) {
+	m, ok := LookupMetric("trigram")
+	if !ok || !m.HigherIsBetter() || m.Score("abc", "abc") != 1 {
+		t.Errorf("expected trigram metric to be registered")
+	}
+}
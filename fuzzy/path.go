@@ -0,0 +1,200 @@
+package fuzzy
+
+import (
+	"golang.org/x/text/transform"
+)
+
+// Penalties added to the distance of a path match, see PathQuery.RankMatch.
+const (
+	pathDirPenalty  = 2 // per rune matched outside the basename
+	pathJumpPenalty = 1 // per run of matched runes not starting at a boundary
+)
+
+// A PathQuery matches slash separated file paths. Matching works like Match,
+// but ranking prefers matches in the basename and at the start of path
+// segments or words, so that "fzgo" ranks "fuzzy/fuzzy.go" above
+// "foo/zz/go.mod". A PathQuery is safe for concurrent use as long as its
+// fields aren't modified.
+type PathQuery struct {
+	// FromEnd makes the query match the rightmost occurrence of each
+	// character, scanning from the end of the path. This is faster than the
+	// default, which searches for the placement of the characters with the
+	// lowest distance, and gives similar results for most queries.
+	FromEnd bool
+
+	source      string
+	sourceT     string
+	transformer func() transform.Transformer
+}
+
+// NewPathQuery returns a PathQuery for source.
+func NewPathQuery(source string) *PathQuery {
+	return newPathQuery(source, noopTransformer)
+}
+
+// NewPathQueryFold is a case-insensitive version of NewPathQuery.
+func NewPathQueryFold(source string) *PathQuery {
+	return newPathQuery(source, foldTransformer)
+}
+
+func newPathQuery(source string, transformer func() transform.Transformer) *PathQuery {
+	return &PathQuery{
+		source:      source,
+		sourceT:     stringTransform(source, transformer()),
+		transformer: transformer,
+	}
+}
+
+// String returns the source text of the query.
+func (q *PathQuery) String() string {
+	return q.source
+}
+
+// Match reports whether the query matches target, see Match.
+func (q *PathQuery) Match(target string) bool {
+	return matchTransformed(q.sourceT, stringTransform(target, q.transformer()))
+}
+
+// Find will return a list of paths in targets that match the query.
+func (q *PathQuery) Find(targets []string) []string {
+	t := q.transformer()
+
+	var matches []string
+
+	for _, target := range targets {
+		if matchTransformed(q.sourceT, stringTransform(target, t)) {
+			matches = append(matches, target)
+		}
+	}
+
+	return matches
+}
+
+// RankMatch is similar to Match except it will return a distance between
+// the query and target, or -1 if there was no match. The distance starts out
+// as the number of unmatched runes, like RankMatch, and is increased for every
+// rune matched in a directory rather than the basename, and for every run of
+// matched runes that doesn't start a path segment or a word.
+func (q *PathQuery) RankMatch(target string) int {
+	distance, _ := q.align([]rune(stringTransform(target, q.transformer())))
+	return distance
+}
+
+// RankFind is similar to Find, except it will also rank all matches, see
+// RankMatch. The Similarity of the ranks is meaningless, since the distance
+// isn't an edit distance.
+func (q *PathQuery) RankFind(targets []string) Ranks {
+	t := q.transformer()
+
+	var r Ranks
+
+	for index, target := range targets {
+		if distance, _ := q.align([]rune(stringTransform(target, t))); distance >= 0 {
+			r = append(r, Rank{q.source, target, distance, index})
+		}
+	}
+
+	return r
+}
+
+// MatchPositions returns the byte offsets in target of the characters
+// matched by the query, using the same placement as RankMatch. If there was
+// no match, it will return nil.
+func (q *PathQuery) MatchPositions(target string) []int {
+	targetT, offsets := stringTransformOffsets(target, q.transformer())
+	_, indices := q.align([]rune(targetT))
+	if indices == nil {
+		return nil
+	}
+
+	positions := make([]int, 0, len(indices))
+	for _, i := range indices {
+		positions = appendPosition(positions, offsets[i])
+	}
+	return positions
+}
+
+// align places the query in target and returns the distance and the matched
+// rune indices, or -1 and nil if there was no match.
+func (q *PathQuery) align(target []rune) (int, []int) {
+	source := []rune(q.sourceT)
+	if len(source) > len(target) {
+		return -1, nil
+	}
+
+	base := len(target) - len(source)
+	if len(source) == 0 {
+		return base, []int{}
+	}
+
+	basename := 0 // index of the first rune of the basename
+	for j, r := range target {
+		if r == '/' {
+			basename = j + 1
+		}
+	}
+
+	cost := func(k, j int) int {
+		c := 0
+		if j < basename {
+			c += pathDirPenalty
+		}
+		if (k < 0 || j != k+1) && !pathBoundary(target, j) {
+			c += pathJumpPenalty
+		}
+		return c
+	}
+
+	var indices []int
+	if q.FromEnd {
+		indices = alignFromEnd(source, target)
+	} else {
+		_, indices = alignRunes(source, target, cost)
+	}
+	if indices == nil {
+		return -1, nil
+	}
+
+	distance := base
+	for i, j := range indices {
+		k := -1
+		if i > 0 {
+			k = indices[i-1]
+		}
+		distance += cost(k, j)
+	}
+
+	return distance, indices
+}
+
+// pathBoundary reports whether the rune at index j starts a path segment or
+// a word.
+func pathBoundary(target []rune, j int) bool {
+	if j == 0 {
+		return true
+	}
+	switch target[j-1] {
+	case '/', '.', '_', '-', ' ':
+		return true
+	}
+	return false
+}
+
+// alignFromEnd matches the rightmost occurrence of each rune of source.
+func alignFromEnd(source, target []rune) []int {
+	indices := make([]int, len(source))
+	j := len(target) - 1
+
+	for i := len(source) - 1; i >= 0; i-- {
+		for j >= 0 && target[j] != source[i] {
+			j--
+		}
+		if j < 0 {
+			return nil
+		}
+		indices[i] = j
+		j--
+	}
+
+	return indices
+}
@@ -0,0 +1,82 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var pathTargets = []string{"foo/zz/go.mod", "fuzzy/fuzzy.go", "fuzzy/fuzzy_test.go", "go/foo.txt", "foo/bar.go"}
+
+func TestPathQueryRankFind(t *testing.T) {
+	for _, fromEnd := range []bool{false, true} {
+		q := NewPathQuery("fzgo")
+		q.FromEnd = fromEnd
+		ranks := q.RankFind(pathTargets)
+		sort.Stable(ranks)
+
+		if len(ranks) != 3 || ranks[0].Target != "fuzzy/fuzzy.go" || ranks[1].Target != "foo/zz/go.mod" {
+			t.Errorf("FromEnd=%t: unexpected order %+v", fromEnd, ranks)
+		}
+	}
+}
+
+func TestPathQueryRankMatch(t *testing.T) {
+	var pathTests = []struct {
+		source  string
+		target  string
+		rank    int
+		fromEnd int
+	}{
+		{"fzgo", "fuzzy/fuzzy.go", 11, 11},
+		{"fzgo", "foo/zz/go.mod", 13, 15},
+		{"go", "go/foo.txt", 11, 11},
+		{"go", "foo/bar.go", 8, 8},
+		{"fo", "foo/bar.go", 11, 11},
+		{"ab", "a/xab", 4, 4},
+		{"ab", "ab_xb", 3, 4},
+		{"", "foo/bar.go", 10, 10},
+		{"xyz", "foo/bar.go", -1, -1},
+	}
+
+	for _, val := range pathTests {
+		q := NewPathQuery(val.source)
+		if rank := q.RankMatch(val.target); rank != val.rank {
+			t.Errorf("expected ranking %d, got %d for %s in %s",
+				val.rank, rank, val.source, val.target)
+		}
+		q.FromEnd = true
+		if rank := q.RankMatch(val.target); rank != val.fromEnd {
+			t.Errorf("expected ranking from end %d, got %d for %s in %s",
+				val.fromEnd, rank, val.source, val.target)
+		}
+	}
+}
+
+func TestPathQueryFind(t *testing.T) {
+	wanted := []string{"fuzzy/fuzzy.go", "fuzzy/fuzzy_test.go"}
+	if matches := NewPathQueryFold("FUZZY/GO").Find(pathTargets); !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+	if !NewPathQuery("fzgo").Match("fuzzy/fuzzy.go") || NewPathQuery("FZGO").Match("fuzzy/fuzzy.go") {
+		t.Errorf("unexpected match result")
+	}
+}
+
+func TestPathQueryMatchPositions(t *testing.T) {
+	q := NewPathQuery("fzgo")
+	if positions, wanted := q.MatchPositions("fuzzy/fuzzy.go"), []int{6, 8, 12, 13}; !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+	if positions := q.MatchPositions("foo/bar.go"); positions != nil {
+		t.Errorf("expected no positions, got %v", positions)
+	}
+}
+
+func ExampleNewPathQuery() {
+	ranks := NewPathQuery("fzgo").RankFind([]string{"foo/zz/go.mod", "fuzzy/fuzzy.go"})
+	sort.Sort(ranks)
+	fmt.Print(ranks[0].Target)
+	// Output: fuzzy/fuzzy.go
+}
@@ -0,0 +1,129 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchPositions is similar to Match except it will return the byte offsets
+// in target of every matched character, which is useful for highlighting. If
+// there was no match, it will return nil.
+func MatchPositions(source, target string) []int {
+	return matchPositions(source, target, noopTransformer())
+}
+
+// MatchPositionsFold is a case-insensitive version of MatchPositions.
+func MatchPositionsFold(source, target string) []int {
+	return matchPositions(source, target, foldTransformer())
+}
+
+// MatchPositionsNormalized is a unicode-normalized version of MatchPositions.
+// The offsets refer to the original, untransformed target.
+func MatchPositionsNormalized(source, target string) []int {
+	return matchPositions(source, target, normalizeTransformer())
+}
+
+// MatchPositionsNormalizedFold is a unicode-normalized and case-insensitive version of MatchPositions.
+func MatchPositionsNormalizedFold(source, target string) []int {
+	return matchPositions(source, target, normalizedFoldTransformer())
+}
+
+// MatchPositionsNormalizedCompat is a unicode compatibility-normalized version
+// of MatchPositions. A character that expands into several characters, like
+// "ﬀ", is reported once at its offset in the original target.
+func MatchPositionsNormalizedCompat(source, target string) []int {
+	return matchPositions(source, target, normalizeCompatTransformer())
+}
+
+// MatchPositionsNormalizedCompatFold is a unicode compatibility-normalized and case-insensitive version of MatchPositions.
+func MatchPositionsNormalizedCompatFold(source, target string) []int {
+	return matchPositions(source, target, normalizedCompatFoldTransformer())
+}
+
+// MatchPositionsTransform is a version of MatchPositions that applies t to
+// both source and target before matching. See MatchTransform.
+func MatchPositionsTransform(source, target string, t transform.Transformer) []int {
+	return matchPositions(source, target, t)
+}
+
+func matchPositions(source, target string, transformer transform.Transformer) []int {
+	sourceT := stringTransform(source, transformer)
+	targetT, offsets := stringTransformOffsets(target, transformer)
+	return matchPositionsTransformed(sourceT, targetT, offsets)
+}
+
+// matchPositionsTransformed performs the same greedy search as
+// matchTransformed but records where each rune of source was found, mapped
+// through offsets.
+func matchPositionsTransformed(source, target string, offsets []int) []int {
+	if len(target) < len(source) {
+		return nil
+	}
+
+	positions := make([]int, 0, utf8.RuneCountInString(source))
+	i, n := 0, 0 // byte offset and rune index into target
+
+Outer:
+	for _, r1 := range source {
+		for i < len(target) {
+			r2, size := utf8.DecodeRuneInString(target[i:])
+			i += size
+			n++
+			if r1 == r2 {
+				positions = appendPosition(positions, offsets[n-1])
+				continue Outer
+			}
+		}
+		return nil
+	}
+
+	return positions
+}
+
+// appendPosition appends offset to positions unless it's already the last
+// element, which happens when a single original character expands into
+// several transformed ones.
+func appendPosition(positions []int, offset int) []int {
+	if len(positions) > 0 && positions[len(positions)-1] == offset {
+		return positions
+	}
+	return append(positions, offset)
+}
+
+// stringTransformOffsets is like stringTransform, but also returns the byte
+// offset in s that produced each rune of the transformed string.
+//
+// The transformation is applied one normalization segment at a time, i.e. a
+// starter and the combining marks following it, which lets every output rune
+// be traced back to the segment it came from.
+func stringTransformOffsets(s string, t transform.Transformer) (string, []int) {
+	if _, ok := t.(nopTransformer); ok {
+		offsets := make([]int, 0, len(s))
+		for i := range s {
+			offsets = append(offsets, i)
+		}
+		return s, offsets
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	offsets := make([]int, 0, len(s))
+
+	for i := 0; i < len(s); {
+		n := norm.NFKC.NextBoundaryInString(s[i:], true)
+		if n <= 0 {
+			n = len(s) - i
+		}
+		segment := stringTransform(s[i:i+n], t)
+		for range segment {
+			offsets = append(offsets, i)
+		}
+		b.WriteString(segment)
+		i += n
+	}
+
+	return b.String(), offsets
+}
@@ -0,0 +1,72 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMatchPositions(t *testing.T) {
+	var positionsTests = []struct {
+		source string
+		target string
+		wanted []int
+	}{
+		{"twl", "cartwheel", []int{3, 4, 8}},
+		{"", "cartwheel", []int{}},
+		{"dog", "cartwheel", nil},
+		{"中国", "中华人民共和国", []int{0, 18}},
+		{"ёлка", "ёлочка", []int{0, 2, 8, 10}},
+	}
+
+	for _, val := range positionsTests {
+		positions := MatchPositions(val.source, val.target)
+		if !reflect.DeepEqual(positions, val.wanted) {
+			t.Errorf("expected positions %v, got %v for %s in %s",
+				val.wanted, positions, val.source, val.target)
+		}
+	}
+}
+
+func TestMatchPositionsTransformed(t *testing.T) {
+	var positionsTests = []struct {
+		name   string
+		fn     func(source, target string) []int
+		source string
+		target string
+		wanted []int
+	}{
+		{"Fold", MatchPositionsFold, "twl", "CARTWHEEL", []int{3, 4, 8}},
+		{"Normalized", MatchPositionsNormalized, "lmn", "limón", []int{0, 2, 5}},
+		{"Normalized", MatchPositionsNormalized, "on", "limón", []int{3, 6}},
+		{"NormalizedFold", MatchPositionsNormalizedFold, "MON", "limón", []int{2, 3, 5}},
+		{"NormalizedCompat", MatchPositionsNormalizedCompat, "ull", "ｆｕｌｌ", []int{3, 6, 9}},
+		{"NormalizedCompat", MatchPositionsNormalizedCompat, "ffi", "aﬀi", []int{1, 4}},
+		{"NormalizedCompat", MatchPositionsNormalizedCompat, "12", "①②", []int{0, 3}},
+		{"NormalizedCompatFold", MatchPositionsNormalizedCompatFold, "KG", "5 ㎏", []int{2}},
+		{"NormalizedCompatFold", MatchPositionsNormalizedCompatFold, "xyz", "ｆｕｌｌ", nil},
+	}
+
+	for _, val := range positionsTests {
+		positions := val.fn(val.source, val.target)
+		if !reflect.DeepEqual(positions, val.wanted) {
+			t.Errorf("%s: expected positions %v, got %v for %s in %s",
+				val.name, val.wanted, positions, val.source, val.target)
+		}
+	}
+}
+
+func TestMatchPositionsAgreesWithMatch(t *testing.T) {
+	for _, val := range fuzzyTests {
+		positions := MatchPositions(val.source, val.target)
+		if (positions != nil) != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got positions %v",
+				val.source, val.target, val.wanted, positions)
+		}
+	}
+}
+
+func ExampleMatchPositions() {
+	fmt.Print(MatchPositions("twl", "cartwheel"))
+	// Output: [3 4 8]
+}
@@ -0,0 +1,370 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+var (
+	// ErrEmptyTerm is reported when a term consists of operators only, like
+	// "!" or "^$".
+	ErrEmptyTerm = errors.New("empty term")
+
+	// ErrMisplacedOr is reported when "|" isn't placed between two terms.
+	ErrMisplacedOr = errors.New(`"|" must separate two terms`)
+)
+
+// A QueryError describes a syntax error in an extended query.
+type QueryError struct {
+	// Query is the query that failed to parse.
+	Query string
+
+	// Offset is the byte offset in Query of the offending term.
+	Offset int
+
+	// Err is the underlying error, ErrEmptyTerm or ErrMisplacedOr.
+	Err error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("fuzzy: invalid query %q at offset %d: %v", e.Query, e.Offset, e.Err)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+type termKind int
+
+const (
+	termFuzzy termKind = iota
+	termExact
+	termPrefix
+	termSuffix
+	termEqual
+)
+
+type queryTerm struct {
+	kind    termKind
+	inverse bool
+	text    string // transformed
+}
+
+// A Query is a compiled extended query, see ParseQuery. A Query is safe for
+// concurrent use.
+type Query struct {
+	source string
+
+	// groups are the terms of the query. All groups have to match, and a group
+	// matches when any of its terms does.
+	groups      [][]queryTerm
+	transformer func() transform.Transformer
+}
+
+// ParseQuery compiles an extended query, using the syntax popularized by fzf.
+// The query consists of space separated terms which all have to match the
+// target:
+//
+//	sbtrkt   fuzzy match, same as Match
+//	'wild    exact match, target contains "wild"
+//	^music   target starts with "music"
+//	.mp3$    target ends with ".mp3"
+//	^go$     target is exactly "go"
+//	!fire    target doesn't contain "fire"
+//	!^music  target doesn't start with "music"
+//	!.mp3$   target doesn't end with ".mp3"
+//	!'fire   target doesn't fuzzy match "fire"
+//
+// Terms separated by "|" form a group where only one of the terms has to
+// match, e.g. "^core go$ | rb$ | py$". A literal space can be escaped with a
+// backslash.
+//
+// A malformed query results in a *QueryError.
+func ParseQuery(query string) (*Query, error) {
+	return parseQuery(query, noopTransformer)
+}
+
+// ParseQueryFold is a case-insensitive version of ParseQuery.
+func ParseQueryFold(query string) (*Query, error) {
+	return parseQuery(query, foldTransformer)
+}
+
+type queryToken struct {
+	text   string
+	offset int
+}
+
+// splitQuery splits query on unescaped white space.
+func splitQuery(query string) []queryToken {
+	var tokens []queryToken
+	var b strings.Builder
+	start := -1
+
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case r == '\\' && strings.HasPrefix(query[i+size:], " "):
+			if start < 0 {
+				start = i
+			}
+			b.WriteByte(' ')
+			size++
+		case unicode.IsSpace(r):
+			if start >= 0 {
+				tokens = append(tokens, queryToken{b.String(), start})
+				b.Reset()
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+			b.WriteString(query[i : i+size])
+		}
+		i += size
+	}
+	if start >= 0 {
+		tokens = append(tokens, queryToken{b.String(), start})
+	}
+
+	return tokens
+}
+
+func parseQuery(query string, transformer func() transform.Transformer) (*Query, error) {
+	q := &Query{source: query, transformer: transformer}
+	t := transformer()
+
+	var group []queryTerm
+	or := false
+	orOffset := 0 // offset of the last "|"
+
+	for _, token := range splitQuery(query) {
+		if token.text == "|" {
+			if len(group) == 0 || or {
+				return nil, &QueryError{query, token.offset, ErrMisplacedOr}
+			}
+			or = true
+			orOffset = token.offset
+			continue
+		}
+
+		term, err := parseTerm(token.text)
+		if err != nil {
+			return nil, &QueryError{query, token.offset, err}
+		}
+		term.text = stringTransform(term.text, t)
+
+		if !or && len(group) > 0 {
+			q.groups = append(q.groups, group)
+			group = nil
+		}
+		group = append(group, term)
+		or = false
+	}
+
+	if or {
+		return nil, &QueryError{query, orOffset, ErrMisplacedOr}
+	}
+	if len(group) > 0 {
+		q.groups = append(q.groups, group)
+	}
+
+	return q, nil
+}
+
+func parseTerm(s string) (queryTerm, error) {
+	var term queryTerm
+
+	if strings.HasPrefix(s, "!") {
+		term.inverse = true
+		s = s[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(s, "'"):
+		s = s[1:]
+		// A negated term is an exact match by default, so quoting it turns
+		// it back into a fuzzy one.
+		if !term.inverse {
+			term.kind = termExact
+		}
+	case strings.HasPrefix(s, "^") && strings.HasSuffix(s, "$") && len(s) > 1:
+		term.kind = termEqual
+		s = s[1 : len(s)-1]
+	case strings.HasPrefix(s, "^"):
+		term.kind = termPrefix
+		s = s[1:]
+	case strings.HasSuffix(s, "$"):
+		term.kind = termSuffix
+		s = s[:len(s)-1]
+	case term.inverse:
+		term.kind = termExact
+	}
+
+	if s == "" {
+		return term, ErrEmptyTerm
+	}
+	term.text = s
+
+	return term, nil
+}
+
+// match reports whether the term matches the transformed target.
+func (t queryTerm) match(target string) bool {
+	var ok bool
+	switch t.kind {
+	case termFuzzy:
+		ok = matchTransformed(t.text, target)
+	case termExact:
+		ok = strings.Contains(target, t.text)
+	case termPrefix:
+		ok = strings.HasPrefix(target, t.text)
+	case termSuffix:
+		ok = strings.HasSuffix(target, t.text)
+	case termEqual:
+		ok = target == t.text
+	}
+	return ok != t.inverse
+}
+
+// positions returns the rune indices in the transformed target matched by a
+// non-inverse term, or nil if there was no match.
+func (t queryTerm) positions(target string) []int {
+	if t.inverse || !t.match(target) {
+		return nil
+	}
+
+	start := 0
+	switch t.kind {
+	case termFuzzy:
+		runeIndices := make([]int, 0, len(target))
+		for range target {
+			runeIndices = append(runeIndices, len(runeIndices))
+		}
+		return matchPositionsTransformed(t.text, target, runeIndices)
+	case termExact:
+		start = strings.Index(target, t.text)
+	case termSuffix:
+		start = len(target) - len(t.text)
+	}
+
+	first := utf8.RuneCountInString(target[:start])
+	n := utf8.RuneCountInString(t.text)
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = first + i
+	}
+	return indices
+}
+
+// String returns the source text of the query.
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match reports whether target matches the query.
+func (q *Query) Match(target string) bool {
+	return q.matchTransformed(stringTransform(target, q.transformer()))
+}
+
+func (q *Query) matchTransformed(target string) bool {
+Groups:
+	for _, group := range q.groups {
+		for _, term := range group {
+			if term.match(target) {
+				continue Groups
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// positionsTransformed returns the sorted rune indices in the transformed
+// target matched by the query, or nil if there was no match. For a group of
+// alternatives, the term matching the most runes is used.
+func (q *Query) positionsTransformed(target string) []int {
+	if !q.matchTransformed(target) {
+		return nil
+	}
+
+	seen := make(map[int]bool)
+	for _, group := range q.groups {
+		var best []int
+		for _, term := range group {
+			if p := term.positions(target); len(p) > len(best) {
+				best = p
+			}
+		}
+		for _, i := range best {
+			seen[i] = true
+		}
+	}
+
+	positions := make([]int, 0, len(seen))
+	for i := range seen {
+		positions = append(positions, i)
+	}
+	sort.Ints(positions)
+
+	return positions
+}
+
+// Find will return a list of strings in targets that match the query.
+func (q *Query) Find(targets []string) []string {
+	t := q.transformer()
+
+	var matches []string
+
+	for _, target := range targets {
+		if q.matchTransformed(stringTransform(target, t)) {
+			matches = append(matches, target)
+		}
+	}
+
+	return matches
+}
+
+// RankFind is similar to Find, except it will also rank all matches. The
+// distance is the number of runes in the target not matched by any term,
+// which for a single fuzzy term is the same as the Levenshtein distance.
+// The Source of each Rank is the query as given to ParseQuery, so the
+// Similarity of the ranks is meaningless.
+func (q *Query) RankFind(targets []string) Ranks {
+	t := q.transformer()
+
+	var r Ranks
+
+	for index, target := range targets {
+		targetT := stringTransform(target, t)
+		if positions := q.positionsTransformed(targetT); positions != nil {
+			distance := utf8.RuneCountInString(targetT) - len(positions)
+			r = append(r, Rank{q.String(), target, distance, index})
+		}
+	}
+
+	return r
+}
+
+// MatchPositions returns the byte offsets in target of every character
+// matched by the query, or nil if there was no match. Inverse terms don't
+// contribute any positions.
+func (q *Query) MatchPositions(target string) []int {
+	targetT, offsets := stringTransformOffsets(target, q.transformer())
+	indices := q.positionsTransformed(targetT)
+	if indices == nil {
+		return nil
+	}
+
+	positions := make([]int, 0, len(indices))
+	for _, i := range indices {
+		positions = appendPosition(positions, offsets[i])
+	}
+	return positions
+}
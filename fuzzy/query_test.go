@@ -0,0 +1,131 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestQueryMatch(t *testing.T) {
+	var queryTests = []struct {
+		query  string
+		target string
+		wanted bool
+	}{
+		{"", "anything", true},
+		{"cwl", "cartwheel", true},
+		{"'cwl", "cartwheel", false},
+		{"'wheel", "cartwheel", true},
+		{"^cart", "cartwheel", true},
+		{"^wheel", "cartwheel", false},
+		{"wheel$", "cartwheel", true},
+		{"cart$", "cartwheel", false},
+		{"^cartwheel$", "cartwheel", true},
+		{"^cart$", "cartwheel", false},
+		{"!dog", "cartwheel", true},
+		{"!wheel", "cartwheel", false},
+		{"!whl", "cartwheel", true},
+		{"!'whl", "cartwheel", false},
+		{"!^cart", "cartwheel", false},
+		{"!el$", "cartwheel", false},
+		{"^core go$ | rb$ | py$", "core/main.go", true},
+		{"^core go$ | rb$ | py$", "core/main.py", true},
+		{"^core go$ | rb$ | py$", "core/main.c", false},
+		{"^core go$ | rb$ | py$", "lib/main.go", false},
+		{`new\ york`, "new york city", true},
+		{`new\ york`, "newyork", false},
+		{"中 国$", "中华人民共和国", true},
+	}
+
+	for _, val := range queryTests {
+		q, err := ParseQuery(val.query)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", val.query, err)
+		}
+		if match := q.Match(val.target); match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.query, val.target, val.wanted, match)
+		}
+	}
+}
+
+func TestParseQueryError(t *testing.T) {
+	var errorTests = []struct {
+		query  string
+		offset int
+		err    error
+	}{
+		{"!", 0, ErrEmptyTerm},
+		{"foo ^", 4, ErrEmptyTerm},
+		{"foo '", 4, ErrEmptyTerm},
+		{"foo $", 4, ErrEmptyTerm},
+		{"^$", 0, ErrEmptyTerm},
+		{"| foo", 0, ErrMisplacedOr},
+		{"foo |", 4, ErrMisplacedOr},
+		{"foo |  ", 4, ErrMisplacedOr},
+		{"foo | | bar", 6, ErrMisplacedOr},
+	}
+
+	for _, val := range errorTests {
+		_, err := ParseQuery(val.query)
+		var qerr *QueryError
+		if !errors.As(err, &qerr) {
+			t.Errorf("expected *QueryError for %q, got %v", val.query, err)
+			continue
+		}
+		if qerr.Offset != val.offset || !errors.Is(err, val.err) {
+			t.Errorf("expected %v at offset %d for %q, got %v at offset %d",
+				val.err, val.offset, val.query, qerr.Err, qerr.Offset)
+		}
+	}
+}
+
+func TestQueryFind(t *testing.T) {
+	targets := []string{"cartwheel", "foobar", "wheel", "baz", "Wheelbarrow"}
+
+	q, _ := ParseQuery("whl !^cart")
+	if matches, wanted := q.Find(targets), []string{"wheel"}; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+
+	q, _ = ParseQueryFold("^whe | baz")
+	if matches, wanted := q.Find(targets), []string{"wheel", "baz", "Wheelbarrow"}; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+}
+
+func TestQueryRankFind(t *testing.T) {
+	targets := []string{"cartwheel", "foobar", "wheel", "baz"}
+
+	q, _ := ParseQuery("whl")
+	ranks := q.RankFind(targets)
+	if wanted := RankFind("whl", targets); !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	q, _ = ParseQuery("^car 'eel !foo")
+	ranks = q.RankFind(targets)
+	if wanted := (Ranks{{"^car 'eel !foo", "cartwheel", 3, 0}}); !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func TestQueryMatchPositions(t *testing.T) {
+	q, _ := ParseQueryFold("^CAR el$ !x")
+	if positions, wanted := q.MatchPositions("Cartwheel"), []int{0, 1, 2, 7, 8}; !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+	if positions := q.MatchPositions("Carthex"); positions != nil {
+		t.Errorf("expected no positions, got %v", positions)
+	}
+}
+
+func ExampleParseQuery() {
+	q, err := ParseQuery("^core go$ | rb$ | py$ !_test")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(q.Find([]string{"core/main.go", "core/main_test.go", "core/util.py", "cmd/main.go"}))
+	// Output: [core/main.go core/util.py]
+}
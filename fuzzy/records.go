@@ -0,0 +1,128 @@
+package fuzzy
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// A Field is a named, searchable value of a Record.
+type Field struct {
+	// Name identifies the field, e.g. "title" or "tags".
+	Name string
+
+	// Value is the text matched against.
+	Value string
+
+	// Weight scales the contribution of the field to the score of the
+	// record. A zero Weight is treated as 1, so a field can't be disabled
+	// with it; leave the field out of the record instead.
+	Weight float64
+}
+
+// A Record is an item made up of several fields, like the name, description
+// and tags of a product.
+type Record []Field
+
+// RankFindRecords will return the records where source matches at least one
+// field, as with Match. Each matching field contributes its weight times the
+// fraction of its runes that were matched to the score of the record, so
+// short fields that match closely count the most.
+func RankFindRecords(source string, records []Record) RecordRanks {
+	return rankFindRecords(source, records, noopTransformer())
+}
+
+// RankFindRecordsFold is a case-insensitive version of RankFindRecords.
+func RankFindRecordsFold(source string, records []Record) RecordRanks {
+	return rankFindRecords(source, records, foldTransformer())
+}
+
+func rankFindRecords(source string, records []Record, transformer transform.Transformer) RecordRanks {
+	sourceT := stringTransform(source, transformer)
+	sourceLen := utf8.RuneCountInString(sourceT)
+
+	var r RecordRanks
+
+	for index, record := range records {
+		var rr RecordRank
+
+		for _, field := range record {
+			valueT, offsets := stringTransformOffsets(field.Value, transformer)
+			positions := matchPositionsTransformed(sourceT, valueT, offsets)
+			if positions == nil {
+				continue
+			}
+
+			valueLen := utf8.RuneCountInString(valueT)
+			weight := field.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			if valueLen > 0 {
+				rr.Score += weight * float64(sourceLen) / float64(valueLen)
+			} else {
+				rr.Score += weight
+			}
+
+			// The source is a subsequence of the value, so the Levenshtein
+			// distance is the difference in length.
+			rr.Matches = append(rr.Matches, FieldMatch{
+				Name:      field.Name,
+				Distance:  valueLen - sourceLen,
+				Positions: positions,
+			})
+		}
+
+		if rr.Matches != nil {
+			rr.Source = source
+			rr.OriginalIndex = index
+			r = append(r, rr)
+		}
+	}
+
+	return r
+}
+
+// A FieldMatch describes how a field of a record was matched.
+type FieldMatch struct {
+	// Name is the name of the matched field.
+	Name string
+
+	// Distance is the Levenshtein distance between the source and the field
+	// value, after the case folding of RankFindRecordsFold if used.
+	Distance int
+
+	// Positions are the byte offsets in the field value of the matched
+	// characters, see MatchPositions.
+	Positions []int
+}
+
+type RecordRank struct {
+	// Source is used as the source for matching.
+	Source string
+
+	// Score is the combined, weighted score of the matched fields. Higher is
+	// better.
+	Score float64
+
+	// Matches holds the matched fields, in the order of the record.
+	Matches []FieldMatch
+
+	// Location of the record in original list
+	OriginalIndex int
+}
+
+type RecordRanks []RecordRank
+
+func (r RecordRanks) Len() int {
+	return len(r)
+}
+
+func (r RecordRanks) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+// Less orders the records by descending score.
+func (r RecordRanks) Less(i, j int) bool {
+	return r[i].Score > r[j].Score
+}
@@ -0,0 +1,73 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var testRecords = []Record{
+	{{"name", "cartwheel", 2}, {"description", "A wheel for a cart", 1}, {"tags", "wheel, wood", 0}},
+	{{"name", "wheelbarrow", 2}, {"description", "Carries things", 1}, {"tags", "garden", 0}},
+	{{"name", "foobar", 2}, {"description", "Nothing to see", 1}, {"tags", "misc", 0}},
+}
+
+func TestRankFindRecords(t *testing.T) {
+	ranks := RankFindRecords("wheel", testRecords)
+
+	wanted := RecordRanks{
+		{"wheel", 2*5.0/9 + 1*5.0/18 + 5.0/11, []FieldMatch{
+			{"name", 4, []int{4, 5, 6, 7, 8}},
+			{"description", 13, []int{2, 3, 4, 5, 6}},
+			{"tags", 6, []int{0, 1, 2, 3, 4}},
+		}, 0},
+		{"wheel", 2 * 5.0 / 11, []FieldMatch{
+			{"name", 6, []int{0, 1, 2, 3, 4}},
+		}, 1},
+	}
+
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func TestRankFindRecordsFold(t *testing.T) {
+	ranks := RankFindRecordsFold("CARRIES", testRecords)
+	if len(ranks) != 1 || ranks[0].OriginalIndex != 1 || ranks[0].Matches[0].Name != "description" {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+	if d := ranks[0].Matches[0].Distance; d != LevenshteinDistance("carries", "carries things") {
+		t.Errorf("expected the case-insensitive Levenshtein distance, got %d", d)
+	}
+	if ranks := RankFindRecords("CARRIES", testRecords); ranks != nil {
+		t.Errorf("expected no ranks, got %+v", ranks)
+	}
+}
+
+func TestSortingRecordRanks(t *testing.T) {
+	rs := RecordRanks{{Score: 1, OriginalIndex: 0}, {Score: 3, OriginalIndex: 1}, {Score: 2, OriginalIndex: 2}}
+
+	sort.Sort(rs)
+
+	for i, wanted := range []int{1, 2, 0} {
+		if rs[i].OriginalIndex != wanted {
+			t.Errorf("expected index %d at %d, got %+v", wanted, i, rs)
+		}
+	}
+}
+
+func ExampleRankFindRecords() {
+	records := []Record{
+		{{Name: "name", Value: "wheelbarrow"}, {Name: "tags", Value: "garden"}},
+		{{Name: "name", Value: "cartwheel", Weight: 2}, {Name: "tags", Value: "wheel, wood"}},
+	}
+	ranks := RankFindRecords("wheel", records)
+	sort.Sort(ranks)
+	for _, r := range ranks {
+		fmt.Printf("%d %.2f %+v\n", r.OriginalIndex, r.Score, r.Matches)
+	}
+	// Output:
+	// 1 1.57 [{Name:name Distance:4 Positions:[4 5 6 7 8]} {Name:tags Distance:6 Positions:[0 1 2 3 4]}]
+	// 0 0.45 [{Name:name Distance:6 Positions:[0 1 2 3 4]}]
+}
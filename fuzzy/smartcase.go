@@ -0,0 +1,78 @@
+package fuzzy
+
+import (
+	"sort"
+	"unicode"
+
+	"golang.org/x/text/transform"
+)
+
+// smartCaseTransformer returns a case-insensitive transformer unless source
+// contains an uppercase or titlecase letter.
+func smartCaseTransformer(source string) transform.Transformer {
+	for _, r := range source {
+		if unicode.IsUpper(r) || unicode.IsTitle(r) {
+			return noopTransformer()
+		}
+	}
+	return foldTransformer()
+}
+
+// MatchSmartCase is a version of Match that is case-insensitive, unless
+// source contains an uppercase letter. This is the "smart case" behaviour
+// known from editors like Vim.
+func MatchSmartCase(source, target string) bool {
+	return match(source, target, smartCaseTransformer(source))
+}
+
+// FindSmartCase is a smart case version of Find. See MatchSmartCase.
+func FindSmartCase(source string, targets []string) []string {
+	return find(source, targets, smartCaseTransformer(source))
+}
+
+// RankMatchSmartCase is a smart case version of RankMatch. See MatchSmartCase.
+func RankMatchSmartCase(source, target string) int {
+	return rank(source, target, smartCaseTransformer(source))
+}
+
+// RankFindSmartCase is a smart case version of RankFind. See MatchSmartCase.
+// When matching case-insensitively, the distance is measured
+// case-insensitively as well.
+func RankFindSmartCase(source string, targets []string) Ranks {
+	return rankFind(source, targets, smartCaseTransformer(source))
+}
+
+// RankFindSmartCasePreferExact is like RankFindSmartCase, but the targets
+// that match with the same case as source, as with Match, are ranked above
+// the ones that only match case-insensitively. The result is sorted that way
+// first, then by distance, then by the order of targets. The distance is
+// measured on the original strings, so case differences count towards it.
+func RankFindSmartCasePreferExact(source string, targets []string) Ranks {
+	r := rankFind(source, targets, smartCaseTransformer(source)).WithOriginalDistance()
+
+	exact := make([]bool, len(r))
+	for i, rank := range r {
+		exact[i] = Match(source, rank.Target)
+	}
+	sort.Stable(preferExact{r, exact})
+
+	return r
+}
+
+// preferExact sorts ranks with exact[i] set first, then by distance.
+type preferExact struct {
+	Ranks
+	exact []bool
+}
+
+func (p preferExact) Swap(i, j int) {
+	p.Ranks.Swap(i, j)
+	p.exact[i], p.exact[j] = p.exact[j], p.exact[i]
+}
+
+func (p preferExact) Less(i, j int) bool {
+	if p.exact[i] != p.exact[j] {
+		return p.exact[i]
+	}
+	return p.Ranks.Less(i, j)
+}
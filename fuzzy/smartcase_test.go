@@ -0,0 +1,79 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchSmartCase(t *testing.T) {
+	var smartCaseTests = []struct {
+		source string
+		target string
+		wanted bool
+	}{
+		{"cart", "CartWheel", true},
+		{"cw", "CartWheel", true},
+		{"CW", "CartWheel", true},
+		{"Cw", "CartWheel", false},
+		{"CART", "cartwheel", false},
+		{"ёлка", "ЁЛОЧКА", true},
+		{"Ёлка", "ёлочка", false},
+		{"ǅ", "ǆ", false}, // titlecase
+		{"ǆ", "ǅ", true},
+		{"123", "a1b2c3", true},
+	}
+
+	for _, val := range smartCaseTests {
+		match := MatchSmartCase(val.source, val.target)
+		if match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+	}
+}
+
+func TestFindSmartCase(t *testing.T) {
+	targets := []string{"cartwheel", "CartWheel", "CARTWHEEL"}
+
+	if matches, wanted := FindSmartCase("cw", targets), targets; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+	if matches, wanted := FindSmartCase("CW", targets), targets[1:]; !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+}
+
+func TestRankMatchSmartCase(t *testing.T) {
+	if rank := RankMatchSmartCase("cart", "CARTWHEEL"); rank != 5 {
+		t.Errorf("expected ranking 5, got %d", rank)
+	}
+	if rank := RankMatchSmartCase("Cart", "CARTWHEEL"); rank != -1 {
+		t.Errorf("expected ranking -1, got %d", rank)
+	}
+}
+
+func TestRankFindSmartCase(t *testing.T) {
+	targets := []string{"WHEEL", "wheel", "cartwheel"}
+
+	ranks := RankFindSmartCase("whl", targets)
+	wanted := Ranks{{"whl", "WHEEL", 2, 0}, {"whl", "wheel", 2, 1}, {"whl", "cartwheel", 6, 2}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	ranks = RankFindSmartCasePreferExact("whl", targets)
+	wanted = Ranks{{"whl", "wheel", 2, 1}, {"whl", "cartwheel", 6, 2}, {"whl", "WHEEL", 5, 0}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	ranks = RankFindSmartCasePreferExact("whl", []string{"WHEEL", "wheelbarrowx", "Wheel", "wheel"})
+	wanted = Ranks{{"whl", "wheel", 2, 3}, {"whl", "wheelbarrowx", 9, 1}, {"whl", "Wheel", 3, 2}, {"whl", "WHEEL", 5, 0}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+
+	if ranks := RankFindSmartCasePreferExact("Whl", targets); ranks != nil {
+		t.Errorf("expected no ranks, got %+v", ranks)
+	}
+}
@@ -0,0 +1,174 @@
+package fuzzy
+
+import "unicode/utf8"
+
+// AlignmentScoring holds the scores used by SmithWaterman and
+// NeedlemanWunsch. All scores are added to the score of an alignment, so
+// penalties should be negative.
+//
+// A gap of length k scores GapOpen + (k-1)*GapExtend. Setting GapOpen and
+// GapExtend to the same value gives linear gap scores, while a GapOpen lower
+// than GapExtend makes a single long gap score better than several short
+// ones.
+type AlignmentScoring struct {
+	// Match is the score of aligning two equal runes.
+	Match int
+
+	// Mismatch is the score of aligning two different runes.
+	Mismatch int
+
+	// GapOpen is the score of the first rune of a gap.
+	GapOpen int
+
+	// GapExtend is the score of every following rune of a gap.
+	GapExtend int
+
+	// Substitution, if non-nil, scores aligning two runes instead of Match
+	// and Mismatch.
+	Substitution SubstitutionMatrix
+}
+
+// A SubstitutionMatrix scores aligning rune a in the source with rune b in
+// the target, e.g. to make some mismatches cheaper than others.
+type SubstitutionMatrix interface {
+	Score(a, b rune) int
+}
+
+// SubstitutionFunc adapts a function to a SubstitutionMatrix.
+type SubstitutionFunc func(a, b rune) int
+
+// Score returns f(a, b).
+func (f SubstitutionFunc) Score(a, b rune) int {
+	return f(a, b)
+}
+
+// DefaultLocalScoring is a reasonable AlignmentScoring for searching text.
+var DefaultLocalScoring = AlignmentScoring{Match: 2, Mismatch: -1, GapOpen: -2, GapExtend: -1}
+
+func (s AlignmentScoring) substitution(a, b rune) int {
+	if s.Substitution != nil {
+		return s.Substitution.Score(a, b)
+	}
+	if a == b {
+		return s.Match
+	}
+	return s.Mismatch
+}
+
+// LocalAlignment is the result of SmithWaterman.
+type LocalAlignment struct {
+	// Score is the score of the best local alignment, or 0 if no part of
+	// source and target scores positively.
+	Score int
+
+	// SourceStart and SourceEnd are the byte offsets of the aligned part of
+	// source, i.e. source[SourceStart:SourceEnd].
+	SourceStart, SourceEnd int
+
+	// TargetStart and TargetEnd are the byte offsets of the aligned part of
+	// target, i.e. target[TargetStart:TargetEnd].
+	TargetStart, TargetEnd int
+}
+
+// SmithWaterman finds the best local alignment between source and target
+// using the Smith-Waterman algorithm with affine gap scores, as described by
+// Gotoh. Unlike LevenshteinDistance, which compares the strings as a whole,
+// it finds the parts of the two strings that are most similar, which is
+// useful for searching within long text. It runs in O(len(source) *
+// len(target)) time and O(len(target)) space, counting runes.
+func SmithWaterman(source, target string, scoring AlignmentScoring) LocalAlignment {
+	const negInf = -int(^uint(0)>>1) / 2
+	r1, r2 := []rune(source), []rune(target)
+	m := len(r2)
+
+	// cell holds the best score of an alignment ending at a position, along
+	// with the rune indices where the alignment starts.
+	type cell struct {
+		score  int
+		si, sj int
+	}
+
+	// h is the best alignment ending with r1[i-1] aligned to r2[j-1] (or the
+	// empty alignment), e with a gap in source and f with a gap in target.
+	hPrev, h := make([]cell, m+1), make([]cell, m+1)
+	f := make([]cell, m+1)
+	for j := range f {
+		f[j].score = negInf
+	}
+
+	var best cell
+	var bestI, bestJ int
+
+	for i := 1; i <= len(r1); i++ {
+		h[0] = cell{}
+		e := cell{score: negInf}
+
+		for j := 1; j <= m; j++ {
+			// Gap in source, consuming r2[j-1].
+			if open := h[j-1].score + scoring.GapOpen; open >= e.score+scoring.GapExtend {
+				e = cell{open, h[j-1].si, h[j-1].sj}
+			} else {
+				e.score += scoring.GapExtend
+			}
+			// Gap in target, consuming r1[i-1].
+			if open := hPrev[j].score + scoring.GapOpen; open >= f[j].score+scoring.GapExtend {
+				f[j] = cell{open, hPrev[j].si, hPrev[j].sj}
+			} else {
+				f[j].score += scoring.GapExtend
+			}
+
+			diag := hPrev[j-1]
+			if diag.score == 0 {
+				diag.si, diag.sj = i-1, j-1
+			}
+			diag.score += scoring.substitution(r1[i-1], r2[j-1])
+
+			c := cell{0, i, j}
+			if diag.score > c.score {
+				c = diag
+			}
+			if e.score > c.score {
+				c = e
+			}
+			if f[j].score > c.score {
+				c = f[j]
+			}
+			h[j] = c
+
+			if c.score > best.score {
+				best, bestI, bestJ = c, i, j
+			}
+		}
+		hPrev, h = h, hPrev
+	}
+
+	if best.score == 0 {
+		return LocalAlignment{}
+	}
+
+	return LocalAlignment{
+		Score:       best.score,
+		SourceStart: runeOffset(source, best.si),
+		SourceEnd:   runeOffset(source, bestI),
+		TargetStart: runeOffset(target, best.sj),
+		TargetEnd:   runeOffset(target, bestJ),
+	}
+}
+
+// runeOffset returns the byte offset of the n-th rune in s.
+func runeOffset(s string, n int) int {
+	offset := 0
+	for ; n > 0 && offset < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// LocalMetric returns a Metric scoring strings by the score of their best
+// local alignment, see SmithWaterman.
+func (s AlignmentScoring) LocalMetric() Metric {
+	return SimilarityFunc(func(source, target string) float64 {
+		return float64(SmithWaterman(source, target, s).Score)
+	})
+}
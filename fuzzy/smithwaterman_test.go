@@ -0,0 +1,77 @@
+package fuzzy
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSmithWaterman(t *testing.T) {
+	var swTests = []struct {
+		source, target string
+		scoring        AlignmentScoring
+		wanted         LocalAlignment
+	}{
+		{
+			"TGTTACGG", "GGTTGACTA",
+			AlignmentScoring{Match: 3, Mismatch: -3, GapOpen: -2, GapExtend: -2},
+			LocalAlignment{13, 1, 6, 1, 7},
+		},
+		{
+			"abc", "xxabcxx",
+			DefaultLocalScoring,
+			LocalAlignment{6, 0, 3, 2, 5},
+		},
+		{
+			"abc", "xyz",
+			DefaultLocalScoring,
+			LocalAlignment{},
+		},
+		{
+			"", "abc",
+			DefaultLocalScoring,
+			LocalAlignment{},
+		},
+		{
+			"ёлка", "новогодняя ёлочка",
+			DefaultLocalScoring,
+			LocalAlignment{5, 0, 8, 21, 33},
+		},
+	}
+
+	for _, test := range swTests {
+		if a := SmithWaterman(test.source, test.target, test.scoring); a != test.wanted {
+			t.Errorf("expected %+v, got %+v for %s in %s", test.wanted, a, test.source, test.target)
+		}
+	}
+}
+
+func TestSmithWatermanAffineGap(t *testing.T) {
+	source, target := "abcdefgh", "abcdxxxxefgh"
+
+	linear := AlignmentScoring{Match: 2, Mismatch: -2, GapOpen: -2, GapExtend: -2}
+	if a := SmithWaterman(source, target, linear); a.Score != 8 {
+		t.Errorf("expected linear score 8, got %+v", a)
+	}
+
+	affine := AlignmentScoring{Match: 2, Mismatch: -2, GapOpen: -3, GapExtend: -1}
+	wanted := LocalAlignment{Score: 10, SourceStart: 0, SourceEnd: 8, TargetStart: 0, TargetEnd: 12}
+	if a := SmithWaterman(source, target, affine); a != wanted {
+		t.Errorf("expected %+v, got %+v", wanted, a)
+	}
+}
+
+func TestLocalMetric(t *testing.T) {
+	targets := []string{"the quick brown fox", "a lazy dog", "quack"}
+	ranks := RankMetric("quick", targets, DefaultLocalScoring.LocalMetric())
+
+	if ranks[0].Target != "the quick brown fox" || ranks[0].Score != 10 || ranks[2].Target != "a lazy dog" {
+		t.Errorf("unexpected ranks %+v", ranks)
+	}
+}
+
+func ExampleSmithWaterman() {
+	text := "All Gaul is divided into three parts"
+	a := SmithWaterman("devided", text, DefaultLocalScoring)
+	fmt.Println(a.Score, text[a.TargetStart:a.TargetEnd])
+	// Output: 11 divided
+}
@@ -0,0 +1,362 @@
+package fuzzy
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A SpellChecker corrects misspelled words using a dictionary of words and
+// their frequencies, with the symmetric delete algorithm popularized by
+// SymSpell. All strings obtained by deleting up to the maximum distance runes
+// from the dictionary words are precomputed, so that looking up a word only
+// has to generate the deletes of the word itself, and compute the distance to
+// the few dictionary words sharing one of them.
+//
+// Words are compared as is, so the dictionary and input usually have to be
+// lowercased first. A SpellChecker is safe for concurrent lookups, but Add
+// must not be called concurrently with any other method.
+type SpellChecker struct {
+	maxDistance int
+	distance    DistanceFunc
+
+	words   map[string]int64    // frequencies
+	deletes map[string][]string // delete → dictionary words
+	total   int64               // sum of all frequencies
+	longest int                 // length in runes of the longest word
+}
+
+// A Correction is a dictionary word suggested for a misspelled word.
+type Correction struct {
+	// Term is the corrected word, or phrase for LookupCompound.
+	Term string
+
+	// Distance is the edit distance between the input and Term.
+	Distance int
+
+	// Frequency is the frequency of Term in the dictionary. For a phrase,
+	// it's the lowest frequency of its words, or 0 if it contains a word that
+	// isn't in the dictionary.
+	Frequency int64
+}
+
+// A Segmentation is the result of SpellChecker.Segment.
+type Segmentation struct {
+	// Segmented is the input split into words.
+	Segmented string
+
+	// Corrected is Segmented with every word corrected.
+	Corrected string
+
+	// Distance is the sum of the edit distances of the corrections.
+	Distance int
+
+	// LogProbability is the sum of the natural logarithms of the
+	// probabilities of the corrected words.
+	LogProbability float64
+}
+
+// NewSpellChecker returns an empty SpellChecker that finds corrections up to
+// maxDistance edits away, as measured by distance, or
+// DamerauLevenshteinDistance if it is nil. The distance has to be
+// LevenshteinDistance, DamerauLevenshteinDistance or another distance where
+// each edit deletes at most one rune from either string, since the
+// candidates are found by deletion. Memory usage grows quickly with
+// maxDistance, which is usually 1 or 2.
+func NewSpellChecker(maxDistance int, distance DistanceFunc) *SpellChecker {
+	if distance == nil {
+		distance = DamerauLevenshteinDistance
+	}
+	return &SpellChecker{
+		maxDistance: maxDistance,
+		distance:    distance,
+		words:       make(map[string]int64),
+		deletes:     make(map[string][]string),
+	}
+}
+
+// Add adds word to the dictionary with the given frequency, which is added
+// to the existing frequency if the word is in the dictionary already. The
+// frequency has to be positive, Add does nothing otherwise.
+func (c *SpellChecker) Add(word string, frequency int64) {
+	if frequency <= 0 {
+		return
+	}
+	c.total += frequency
+	if _, ok := c.words[word]; ok {
+		c.words[word] += frequency
+		return
+	}
+	c.words[word] = frequency
+	c.longest = max2(c.longest, utf8.RuneCountInString(word))
+
+	forEachDelete(word, c.maxDistance, func(d string) {
+		c.deletes[d] = append(c.deletes[d], word)
+	})
+}
+
+// Frequency returns the frequency of word in the dictionary, or 0 if it's not
+// in the dictionary.
+func (c *SpellChecker) Frequency(word string) int64 {
+	return c.words[word]
+}
+
+// forEachDelete calls fn for s and every distinct string obtained by deleting
+// up to n runes from s.
+func forEachDelete(s string, n int, fn func(string)) {
+	seen := map[string]bool{s: true}
+	level := []string{s}
+	fn(s)
+
+	for depth := 0; depth < n && len(level) > 0; depth++ {
+		var next []string
+		for _, w := range level {
+			for i := range w {
+				_, size := utf8.DecodeRuneInString(w[i:])
+				d := w[:i] + w[i+size:]
+				if !seen[d] {
+					seen[d] = true
+					next = append(next, d)
+					fn(d)
+				}
+			}
+		}
+		level = next
+	}
+}
+
+// Lookup returns the dictionary words within maxDistance edits of input,
+// sorted by distance, then by descending frequency. maxDistance is limited to
+// the maximum distance of the checker.
+func (c *SpellChecker) Lookup(input string, maxDistance int) []Correction {
+	maxDistance = min2(maxDistance, c.maxDistance)
+	if maxDistance < 0 {
+		return nil
+	}
+	length := utf8.RuneCountInString(input)
+	if length-maxDistance > c.longest {
+		return nil
+	}
+
+	candidates := make(map[string]bool)
+	forEachDelete(input, maxDistance, func(d string) {
+		for _, word := range c.deletes[d] {
+			candidates[word] = true
+		}
+	})
+
+	var corrections []Correction
+
+	for word := range candidates {
+		if abs(utf8.RuneCountInString(word)-length) > maxDistance {
+			continue
+		}
+		if d := c.distance(input, word); d <= maxDistance {
+			corrections = append(corrections, Correction{word, d, c.words[word]})
+		}
+	}
+
+	sort.Slice(corrections, func(i, j int) bool {
+		a, b := corrections[i], corrections[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Frequency != b.Frequency {
+			return a.Frequency > b.Frequency
+		}
+		return a.Term < b.Term
+	})
+
+	return corrections
+}
+
+// best returns the best correction of word within maxDistance, if any.
+func (c *SpellChecker) best(word string, maxDistance int) (Correction, bool) {
+	corrections := c.Lookup(word, maxDistance)
+	if len(corrections) == 0 {
+		return Correction{}, false
+	}
+	return corrections[0], true
+}
+
+// logProbability returns the natural logarithm of the probability of a
+// word with the given frequency, or of an unknown word of the given length.
+// The probability of an unknown word decreases with its length, so that
+// splitting it into known words is preferred.
+func (c *SpellChecker) logProbability(frequency int64, length int) float64 {
+	total := math.Log(float64(max64(c.total, 1)))
+	if frequency > 0 {
+		return math.Log(float64(frequency)) - total
+	}
+	return math.Log(10) - total - float64(length)*math.Log(10)
+}
+
+// phrase is a partial result of LookupCompound and Segment.
+type phrase struct {
+	words     []string
+	segments  []string // uncorrected words, for Segment
+	cost      int
+	logProb   float64
+	frequency int64
+	ok        bool
+}
+
+// better reports whether p has a lower cost than q, or the same cost and a
+// higher probability.
+func (p phrase) better(q phrase) bool {
+	if !q.ok {
+		return p.ok
+	}
+	if p.cost != q.cost {
+		return p.cost < q.cost
+	}
+	return p.logProb > q.logProb
+}
+
+// extend returns p followed by the corrected word.
+func (p phrase) extend(segment string, correction Correction, cost int, logProb float64) phrase {
+	frequency := correction.Frequency
+	if len(p.words) > 0 {
+		frequency = min64(p.frequency, frequency)
+	}
+	return phrase{
+		words:     append(p.words[:len(p.words):len(p.words)], correction.Term),
+		segments:  append(p.segments[:len(p.segments):len(p.segments)], segment),
+		cost:      p.cost + cost,
+		logProb:   p.logProb + logProb,
+		frequency: frequency,
+		ok:        true,
+	}
+}
+
+// LookupCompound corrects a phrase of white space separated words, which
+// may also contain spaces inserted into or missing between words, like
+// "whereis th elove". Every word is corrected with at most maxDistance edits,
+// two adjacent words may be joined and a word may be split in two. The
+// combination with the lowest total distance, preferring more probable words,
+// is returned. Words that can't be corrected are kept as is.
+func (c *SpellChecker) LookupCompound(input string, maxDistance int) Correction {
+	tokens := strings.Fields(input)
+
+	// best[i] is the best correction of tokens[:i].
+	best := make([]phrase, len(tokens)+1)
+	best[0] = phrase{ok: true}
+
+	for i := range tokens {
+		if !best[i].ok {
+			continue
+		}
+		token := tokens[i]
+
+		// The token as a single word, or unknown.
+		single, ok := c.best(token, maxDistance)
+		var p phrase
+		if ok {
+			p = best[i].extend(token, single, single.Distance, c.logProbability(single.Frequency, 0))
+		} else {
+			unknown := Correction{token, 0, 0}
+			p = best[i].extend(token, unknown, maxDistance+1, c.logProbability(0, utf8.RuneCountInString(token)))
+		}
+
+		// The token split in two words, with the inserted space as an edit.
+		if !ok || single.Distance > 0 {
+			for j := range token {
+				if j == 0 {
+					continue
+				}
+				first, ok1 := c.best(token[:j], maxDistance)
+				second, ok2 := c.best(token[j:], maxDistance)
+				if !ok1 || !ok2 {
+					continue
+				}
+				split := best[i].
+					extend(token[:j], first, first.Distance+1, c.logProbability(first.Frequency, 0)).
+					extend(token[j:], second, second.Distance, c.logProbability(second.Frequency, 0))
+				if split.better(p) {
+					p = split
+				}
+			}
+		}
+
+		if p.better(best[i+1]) {
+			best[i+1] = p
+		}
+
+		// The token joined with the next one, with the removed space as an
+		// edit.
+		if i+1 < len(tokens) {
+			joined := token + tokens[i+1]
+			if correction, ok := c.best(joined, maxDistance); ok {
+				p := best[i].extend(joined, correction, correction.Distance+1, c.logProbability(correction.Frequency, 0))
+				if p.better(best[i+2]) {
+					best[i+2] = p
+				}
+			}
+		}
+	}
+
+	result := best[len(tokens)]
+	term := strings.Join(result.words, " ")
+	return Correction{term, c.distance(strings.Join(tokens, " "), term), result.frequency}
+}
+
+// Segment splits input, with any white space removed, into words, like
+// "thequickbrownfox" into "the quick brown fox", correcting every word with
+// at most maxDistance edits. The segmentation with the lowest total distance,
+// preferring more probable words, is returned. Parts that can't be corrected
+// count as completely misspelled.
+func (c *SpellChecker) Segment(input string, maxDistance int) Segmentation {
+	runes := []rune(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, input))
+
+	// Longer parts are too far from every word to be corrected.
+	maxLength := max2(c.longest+max2(min2(maxDistance, c.maxDistance), 0), 1)
+
+	// best[i] is the best segmentation of runes[:i].
+	best := make([]phrase, len(runes)+1)
+	best[0] = phrase{ok: true}
+
+	for i := 1; i <= len(runes); i++ {
+		for j := max2(0, i-maxLength); j < i; j++ {
+			part := string(runes[j:i])
+			var p phrase
+			if correction, ok := c.best(part, maxDistance); ok {
+				p = best[j].extend(part, correction, correction.Distance, c.logProbability(correction.Frequency, 0))
+			} else {
+				unknown := Correction{part, 0, 0}
+				p = best[j].extend(part, unknown, i-j, c.logProbability(0, i-j))
+			}
+			if p.better(best[i]) {
+				best[i] = p
+			}
+		}
+	}
+
+	result := best[len(runes)]
+	return Segmentation{
+		Segmented:      strings.Join(result.segments, " "),
+		Corrected:      strings.Join(result.words, " "),
+		Distance:       result.cost,
+		LogProbability: result.logProb,
+	}
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
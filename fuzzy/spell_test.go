@@ -0,0 +1,158 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func newTestSpellChecker(distance DistanceFunc) *SpellChecker {
+	c := NewSpellChecker(2, distance)
+	for word, frequency := range map[string]int64{
+		"the": 1000, "quick": 50, "brown": 40, "fox": 30, "jumps": 20, "over": 200,
+		"lazy": 10, "dog": 60, "where": 100, "is": 500, "love": 80, "he": 300,
+		"had": 150, "dated": 5, "for": 400, "much": 90, "of": 900, "past": 70,
+		"who": 120, "a": 800, "in": 700, "to": 850, "get": 110, "her": 130,
+		"together": 45, "inter": 3,
+	} {
+		c.Add(word, frequency)
+	}
+	return c
+}
+
+func TestSpellCheckerLookup(t *testing.T) {
+	c := newTestSpellChecker(nil)
+
+	var lookupTests = []struct {
+		input       string
+		maxDistance int
+		wanted      []Correction
+	}{
+		{"the", 0, []Correction{{"the", 0, 1000}}},
+		{"teh", 1, []Correction{{"the", 1, 1000}}},
+		{"teh", 2, []Correction{{"the", 1, 1000}, {"to", 2, 850}, {"he", 2, 300}, {"her", 2, 130}, {"get", 2, 110}}},
+		{"quikc", 2, []Correction{{"quick", 1, 50}}},
+		{"xyzzy", 2, nil},
+		{"brown", 5, []Correction{{"brown", 0, 40}}},
+		{"fox", -1, nil},
+	}
+
+	for _, test := range lookupTests {
+		got := c.Lookup(test.input, test.maxDistance)
+		if !reflect.DeepEqual(got, test.wanted) {
+			t.Errorf("got %v, expected %v for %s within %d", got, test.wanted, test.input, test.maxDistance)
+		}
+	}
+
+	c.Add("the", 10)
+	if c.Frequency("the") != 1010 || c.Frequency("thee") != 0 {
+		t.Errorf("unexpected frequencies")
+	}
+
+	c.Add("the", -2000)
+	c.Add("thee", 0)
+	if c.Frequency("the") != 1010 || c.Frequency("thee") != 0 || c.Lookup("thee", 0) != nil {
+		t.Errorf("expected non-positive frequencies to be ignored")
+	}
+	if p := c.Segment("thequickbrownfox", 1).LogProbability; math.IsNaN(p) || p >= 0 {
+		t.Errorf("unexpected log probability %v", p)
+	}
+}
+
+func TestSpellCheckerLookupAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := randomWords(rng, 300)
+
+	for _, distance := range []DistanceFunc{LevenshteinDistance, DamerauLevenshteinDistance} {
+		c := NewSpellChecker(2, distance)
+		for _, w := range words {
+			c.Add(w, 1)
+		}
+
+		for _, input := range randomWords(rng, 50) {
+			for k := 0; k <= 2; k++ {
+				var want []string
+				for w := range c.words {
+					if distance(input, w) <= k {
+						want = append(want, w)
+					}
+				}
+				var got []string
+				for _, correction := range c.Lookup(input, k) {
+					got = append(got, correction.Term)
+				}
+				sort.Strings(want)
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("Lookup(%q, %d): got %q, expected %q", input, k, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSpellCheckerLookupCompound(t *testing.T) {
+	c := newTestSpellChecker(nil)
+
+	var compoundTests = []struct {
+		input  string
+		wanted Correction
+	}{
+		{"", Correction{"", 0, 0}},
+		{"the quick brown fox", Correction{"the quick brown fox", 0, 30}},
+		{"teh qiuck brwon fox", Correction{"the quick brown fox", 3, 30}},
+		{"whereis th elove", Correction{"where is the love", 2, 80}},
+		{"the quickbrown fox", Correction{"the quick brown fox", 1, 30}},
+		{"the xyzzyq fox", Correction{"the xyzzyq fox", 0, 0}},
+	}
+
+	for _, test := range compoundTests {
+		if got := c.LookupCompound(test.input, 2); !reflect.DeepEqual(got, test.wanted) {
+			t.Errorf("got %+v, expected %+v for %s", got, test.wanted, test.input)
+		}
+	}
+}
+
+func TestSpellCheckerSegment(t *testing.T) {
+	c := newTestSpellChecker(nil)
+
+	var segmentTests = []struct {
+		input                string
+		segmented, corrected string
+		distance             int
+	}{
+		{"", "", "", 0},
+		{"thequickbrownfox", "the quick brown fox", "the quick brown fox", 0},
+		{"thequikbrownfox", "the quik brown fox", "the quick brown fox", 1},
+		{"together", "together", "together", 0},
+		{"inthe", "in the", "in the", 0},
+		{"the lazy dog", "the lazy dog", "the lazy dog", 0},
+	}
+
+	for _, test := range segmentTests {
+		got := c.Segment(test.input, 1)
+		if got.Segmented != test.segmented || got.Corrected != test.corrected || got.Distance != test.distance {
+			t.Errorf("got %+v, expected %q, %q and %d for %s", got, test.segmented, test.corrected, test.distance, test.input)
+		}
+	}
+
+	// The distance is limited to the maximum distance of the checker.
+	if got, wanted := c.Segment("thequikbrownfox", 1000), c.Segment("thequikbrownfox", 2); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("got %+v, expected %+v", got, wanted)
+	}
+}
+
+func ExampleSpellChecker_LookupCompound() {
+	c := NewSpellChecker(2, nil)
+	for _, word := range []string{"where", "is", "the", "love"} {
+		c.Add(word, 1)
+	}
+	fmt.Println(c.LookupCompound("whereis th elove", 2).Term)
+	fmt.Println(c.Segment("whereisthelove", 2).Corrected)
+	// Output:
+	// where is the love
+	// where is the love
+}
@@ -0,0 +1,120 @@
+package fuzzy
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// minSuggestAbbreviation is the length in runes of the shortest input that
+// Suggest treats as an abbreviation.
+const minSuggestAbbreviation = 3
+
+// SuggestOptions configures Suggest. The zero value gives sensible defaults
+// for suggesting commands, flags and other identifiers.
+type SuggestOptions struct {
+	// MaxResults is the maximum number of suggestions, 3 if zero.
+	MaxResults int
+
+	// MaxDistance is the maximum edit distance of a suggestion. If zero, it's
+	// a third of the length of the input, but at least 1 and less than the
+	// length of the input, so that a short input isn't completely replaced.
+	// If negative, no edits are allowed, so only candidates equal to the
+	// input, or abbreviated by it, are suggested.
+	MaxDistance int
+
+	// CaseSensitive disables the default case-insensitive comparison.
+	CaseSensitive bool
+}
+
+// Suggest returns the candidates that input is most likely a mistyped
+// version of, most likely first, e.g. to print "did you mean ...?" for an
+// unknown command. A candidate is suggested if it's within a few edits of
+// input, counting transpositions as a single edit like
+// DamerauLevenshteinDistance, or if input fuzzy matches it like Match, so that
+// abbreviations are suggested too. Inputs shorter than three runes only
+// suggest candidates within the maximum distance, since they fuzzy match too
+// many unrelated candidates. Duplicate candidates are suggested once.
+//
+// Suggestions are ordered by their confidence: the similarity of the edit
+// distance relative to the longer of the two, or, for abbreviations, the
+// fraction of the candidate typed, whichever is higher. It returns nil if
+// there are no suggestions.
+func Suggest(input string, candidates []string, opts SuggestOptions) []string {
+	transformer := foldTransformer()
+	if opts.CaseSensitive {
+		transformer = noopTransformer()
+	}
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 3
+	}
+
+	inputT := stringTransform(input, transformer)
+	length := utf8.RuneCountInString(inputT)
+
+	maxDistance := opts.MaxDistance
+	switch {
+	case maxDistance < 0:
+		maxDistance = 0
+	case maxDistance == 0:
+		maxDistance = max2(1, min2(length/3, length-1))
+		if length <= 1 {
+			maxDistance = 0
+		}
+	}
+
+	type suggestion struct {
+		candidate  string
+		confidence float64
+		distance   int
+	}
+	var suggestions []suggestion
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue
+		}
+		seen[candidate] = true
+
+		candidateT := stringTransform(candidate, transformer)
+		candidateLength := utf8.RuneCountInString(candidateT)
+
+		ok := false
+		confidence := 0.0
+
+		distance := DamerauLevenshteinDistance(inputT, candidateT)
+		if distance <= maxDistance {
+			ok = true
+			confidence = similarity(distance, max2(length, candidateLength))
+		}
+		if length >= minSuggestAbbreviation && matchTransformed(inputT, candidateT) {
+			ok = true
+			if c := float64(length) / float64(candidateLength); c > confidence {
+				confidence = c
+			}
+		}
+
+		if ok {
+			suggestions = append(suggestions, suggestion{candidate, confidence, distance})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].confidence != suggestions[j].confidence {
+			return suggestions[i].confidence > suggestions[j].confidence
+		}
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	if len(suggestions) > maxResults {
+		suggestions = suggestions[:maxResults]
+	}
+
+	var result []string
+	for _, s := range suggestions {
+		result = append(result, s.candidate)
+	}
+
+	return result
+}
@@ -0,0 +1,55 @@
+package fuzzy
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	commands := []string{"build", "bench", "clean", "commit", "config", "install", "list", "test", "verbose", "version", "vet"}
+
+	var suggestTests = []struct {
+		input  string
+		opts   SuggestOptions
+		wanted []string
+	}{
+		{"biuld", SuggestOptions{}, []string{"build"}},
+		{"tset", SuggestOptions{}, []string{"test"}},
+		{"verbos", SuggestOptions{}, []string{"verbose"}},
+		{"verbos", SuggestOptions{MaxDistance: 3}, []string{"verbose", "version"}},
+		{"VERBOSE", SuggestOptions{}, []string{"verbose"}},
+		{"VERBOSE", SuggestOptions{CaseSensitive: true}, nil},
+		{"inst", SuggestOptions{}, []string{"install"}},
+		{"comit", SuggestOptions{}, []string{"commit"}},
+		{"comit", SuggestOptions{MaxDistance: 4}, []string{"commit", "config", "clean"}},
+		{"comit", SuggestOptions{MaxDistance: 4, MaxResults: 1}, []string{"commit"}},
+		{"lsit", SuggestOptions{}, []string{"list"}},
+		{"x", SuggestOptions{}, nil},
+		{"xy", SuggestOptions{}, nil},
+		{"ve", SuggestOptions{}, []string{"vet"}},
+		{"e", SuggestOptions{MaxDistance: 1}, nil},
+		{"ver", SuggestOptions{}, []string{"vet", "verbose", "version"}},
+		{"biuld", SuggestOptions{MaxDistance: -1}, nil},
+		{"Build", SuggestOptions{MaxDistance: -1}, []string{"build"}},
+		{"inst", SuggestOptions{MaxDistance: -1}, []string{"install"}},
+		{"deploy", SuggestOptions{}, nil},
+	}
+
+	for _, test := range suggestTests {
+		if got := Suggest(test.input, commands, test.opts); !reflect.DeepEqual(got, test.wanted) {
+			t.Errorf("got %q, expected %q for %s", got, test.wanted, test.input)
+		}
+	}
+
+	if got := Suggest("tset", []string{"test", "test"}, SuggestOptions{}); !reflect.DeepEqual(got, []string{"test"}) {
+		t.Errorf("expected duplicates to be suggested once, got %q", got)
+	}
+}
+
+func ExampleSuggest() {
+	flags := []string{"verbose", "version", "output", "quiet"}
+	fmt.Println(Suggest("vrebose", flags, SuggestOptions{}))
+	// Output:
+	// [verbose]
+}
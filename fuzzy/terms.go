@@ -0,0 +1,239 @@
+package fuzzy
+
+import (
+	"encoding/binary"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// Terms is a query made up of space separated terms that each have to fuzzy
+// match the target, in any order. For example "wheel cart" matches
+// "cartwheel", which Match would reject since the runes are out of order.
+// Terms is safe for concurrent use as long as its fields aren't modified.
+type Terms struct {
+	// Disjoint requires that the terms match different runes in the target,
+	// so that "ab ab" matches "abab" but not "ab".
+	Disjoint bool
+
+	source      string
+	terms       []string // transformed
+	transformer func() transform.Transformer
+}
+
+// NewTerms splits source on white space into terms.
+func NewTerms(source string) *Terms {
+	return newTerms(source, noopTransformer)
+}
+
+// NewTermsFold is a case-insensitive version of NewTerms.
+func NewTermsFold(source string) *Terms {
+	return newTerms(source, foldTransformer)
+}
+
+func newTerms(source string, transformer func() transform.Transformer) *Terms {
+	t := transformer()
+	fields := strings.Fields(source)
+	terms := make([]string, len(fields))
+	for i, f := range fields {
+		terms[i] = stringTransform(f, t)
+	}
+	return &Terms{source: source, terms: terms, transformer: transformer}
+}
+
+// String returns the source text of the terms.
+func (t *Terms) String() string {
+	return t.source
+}
+
+// Match reports whether every term fuzzy matches target.
+func (t *Terms) Match(target string) bool {
+	return t.matchTransformed(stringTransform(target, t.transformer()))
+}
+
+func (t *Terms) matchTransformed(target string) bool {
+	if t.Disjoint {
+		return t.positionsTransformed(target) != nil
+	}
+	for _, term := range t.terms {
+		if !matchTransformed(term, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// positionsTransformed returns the rune indices in the transformed target
+// matched by each term, or nil if there was no match.
+func (t *Terms) positionsTransformed(target string) [][]int {
+	runes := []rune(target)
+	positions := make([][]int, len(t.terms))
+
+	if !t.Disjoint {
+		for i, term := range t.terms {
+			if positions[i] = matchRunes([]rune(term), runes); positions[i] == nil {
+				return nil
+			}
+		}
+		return positions
+	}
+
+	terms := make([][]rune, len(t.terms))
+	remaining := 0
+	for i, term := range t.terms {
+		terms[i] = []rune(term)
+		positions[i] = make([]int, len(terms[i]))
+		remaining += len(terms[i])
+	}
+
+	// progress[i] is the number of runes of terms[i] matched so far. The
+	// states (j, progress) from which the rest of the terms can't be matched
+	// in runes[j:] are remembered, so every state is explored at most once,
+	// which takes time polynomial in the length of target for a fixed number
+	// of terms.
+	progress := make([]int, len(terms))
+	failed := make(map[string]bool)
+
+	// assign matches the remaining runes of the terms in runes[j:], trying
+	// to match runes[j] before skipping it, so the result is the greedy match
+	// whenever that succeeds.
+	var assign func(j, remaining int) bool
+	assign = func(j, remaining int) bool {
+		if remaining == 0 {
+			return true
+		}
+		if len(runes)-j < remaining {
+			return false
+		}
+
+		b := binary.AppendUvarint(nil, uint64(j))
+		for _, p := range progress {
+			b = binary.AppendUvarint(b, uint64(p))
+		}
+		key := string(b)
+		if failed[key] {
+			return false
+		}
+
+		for i, term := range terms {
+			p := progress[i]
+			if p == len(term) || term[p] != runes[j] {
+				continue
+			}
+			positions[i][p] = j
+			progress[i]++
+			ok := assign(j+1, remaining-1)
+			progress[i]--
+			if ok {
+				return true
+			}
+		}
+		if assign(j+1, remaining) {
+			return true
+		}
+
+		failed[key] = true
+		return false
+	}
+
+	if !assign(0, remaining) {
+		return nil
+	}
+	return positions
+}
+
+// matchRunes greedily finds source as a subsequence of target and returns the
+// matched indices or nil.
+func matchRunes(source, target []rune) []int {
+	indices := make([]int, 0, len(source))
+	i := 0
+
+Outer:
+	for _, r1 := range source {
+		for ; i < len(target); i++ {
+			if r1 == target[i] {
+				indices = append(indices, i)
+				i++
+				continue Outer
+			}
+		}
+		return nil
+	}
+
+	return indices
+}
+
+// Find will return a list of strings in targets that match all terms.
+func (t *Terms) Find(targets []string) []string {
+	tr := t.transformer()
+
+	var matches []string
+
+	for _, target := range targets {
+		if t.matchTransformed(stringTransform(target, tr)) {
+			matches = append(matches, target)
+		}
+	}
+
+	return matches
+}
+
+// RankMatch is similar to Match except it will return the number of runes in
+// target that aren't matched by any term, or -1 if there was no match. For a
+// single term it's the same as RankMatch.
+func (t *Terms) RankMatch(target string) int {
+	return t.rankTransformed(stringTransform(target, t.transformer()))
+}
+
+func (t *Terms) rankTransformed(target string) int {
+	positions := t.positionsTransformed(target)
+	if positions == nil {
+		return -1
+	}
+
+	matched := make(map[int]bool)
+	for _, p := range positions {
+		for _, i := range p {
+			matched[i] = true
+		}
+	}
+
+	return utf8.RuneCountInString(target) - len(matched)
+}
+
+// RankFind is similar to Find, except it will also rank all matches, see
+// RankMatch. The Source of each Rank is the source given to NewTerms, so the
+// Similarity of the ranks is meaningless.
+func (t *Terms) RankFind(targets []string) Ranks {
+	tr := t.transformer()
+
+	var r Ranks
+
+	for index, target := range targets {
+		if distance := t.rankTransformed(stringTransform(target, tr)); distance >= 0 {
+			r = append(r, Rank{t.source, target, distance, index})
+		}
+	}
+
+	return r
+}
+
+// MatchPositions returns, for each term, the byte offsets in target of the
+// characters it matched. If there was no match, it will return nil.
+func (t *Terms) MatchPositions(target string) [][]int {
+	targetT, offsets := stringTransformOffsets(target, t.transformer())
+	indices := t.positionsTransformed(targetT)
+	if indices == nil {
+		return nil
+	}
+
+	positions := make([][]int, len(indices))
+	for i, p := range indices {
+		positions[i] = make([]int, 0, len(p))
+		for _, j := range p {
+			positions[i] = appendPosition(positions[i], offsets[j])
+		}
+	}
+	return positions
+}
@@ -0,0 +1,194 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTermsMatch(t *testing.T) {
+	var termsTests = []struct {
+		source   string
+		target   string
+		wanted   bool
+		disjoint bool
+	}{
+		{"wheel cart", "cartwheel", true, true},
+		{"whl crt", "cartwheel", true, true},
+		{"", "cartwheel", true, true},
+		{"dog cart", "cartwheel", false, false},
+		{"ab ab", "abab", true, true},
+		{"ab ab", "ab", true, false},
+		{"a ab", "ab", true, false},
+		{"ab a", "aab", true, true},
+		{"art cart", "cartwheel", true, false},
+		{"中国 人民", "中华人民共和国", true, true},
+		{"aba ac", "abaca", true, true},
+		{"cbc cab", "caacbcb", true, true},
+	}
+
+	for _, val := range termsTests {
+		terms := NewTerms(val.source)
+		if match := terms.Match(val.target); match != val.wanted {
+			t.Errorf("%s in %s expected match to be %t, got %t",
+				val.source, val.target, val.wanted, match)
+		}
+		terms.Disjoint = true
+		if match := terms.Match(val.target); match != val.disjoint {
+			t.Errorf("%s in %s expected disjoint match to be %t, got %t",
+				val.source, val.target, val.disjoint, match)
+		}
+	}
+}
+
+// disjointBruteForce reports whether terms match disjoint runes of target by
+// trying every labeling of the runes of target with a term or none.
+func disjointBruteForce(terms []string, target string) bool {
+	runes := []rune(target)
+	labels := make([]int, len(runes))
+
+	var try func(i int) bool
+	try = func(i int) bool {
+		if i == len(runes) {
+			for k, term := range terms {
+				var matched []rune
+				for j, l := range labels {
+					if l == k {
+						matched = append(matched, runes[j])
+					}
+				}
+				if string(matched) != term {
+					return false
+				}
+			}
+			return true
+		}
+		for l := -1; l < len(terms); l++ {
+			labels[i] = l
+			if try(i + 1) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return try(0)
+}
+
+func TestTermsDisjointAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.Intn(3)]
+		}
+		return string(b)
+	}
+
+	for n := 0; n < 2000; n++ {
+		terms := []string{word(1 + rng.Intn(3)), word(1 + rng.Intn(3))}
+		target := word(rng.Intn(8))
+
+		source := strings.Join(terms, " ")
+		tt := NewTerms(source)
+		tt.Disjoint = true
+		wanted := disjointBruteForce(terms, target)
+		if match := tt.Match(target); match != wanted {
+			t.Fatalf("%s in %s expected disjoint match to be %t, got %t", source, target, wanted, match)
+		}
+		if !wanted {
+			continue
+		}
+
+		used := make(map[int]bool)
+		for k, p := range tt.MatchPositions(target) {
+			var matched []byte
+			for _, i := range p {
+				if used[i] {
+					t.Fatalf("%s in %s matched byte %d twice", source, target, i)
+				}
+				used[i] = true
+				matched = append(matched, target[i])
+			}
+			if string(matched) != terms[k] {
+				t.Fatalf("%s in %s matched %q for term %q", source, target, matched, terms[k])
+			}
+		}
+	}
+}
+
+func TestTermsDisjointRepeatedRunes(t *testing.T) {
+	// Runes shared by many terms used to make the search exponential.
+	terms := NewTerms("aaaaaaab aaaaaaab aaaaaaab")
+	terms.Disjoint = true
+	if !terms.Match(strings.Repeat("a", 40) + "bbb") {
+		t.Errorf("expected a match")
+	}
+	if terms.Match(strings.Repeat("a", 40) + "bb") {
+		t.Errorf("expected no match")
+	}
+}
+
+func TestTermsFind(t *testing.T) {
+	targets := []string{"Sales Report Q3", "Q3 report", "Sales Q3", "report"}
+	wanted := []string{"Sales Report Q3"}
+
+	if matches := NewTermsFold("report q3 sales").Find(targets); !reflect.DeepEqual(matches, wanted) {
+		t.Errorf("expected %s, got %s", wanted, matches)
+	}
+	if matches := NewTerms("report q3 sales").Find(targets); matches != nil {
+		t.Errorf("expected no matches, got %s", matches)
+	}
+}
+
+func TestTermsRank(t *testing.T) {
+	terms := NewTerms("wheel cart")
+
+	if rank := terms.RankMatch("cartwheel"); rank != 0 {
+		t.Errorf("expected ranking 0, got %d", rank)
+	}
+	if rank := terms.RankMatch("cartwheels"); rank != 1 {
+		t.Errorf("expected ranking 1, got %d", rank)
+	}
+	if rank := terms.RankMatch("wheel"); rank != -1 {
+		t.Errorf("expected ranking -1, got %d", rank)
+	}
+	for _, val := range fuzzyTests {
+		if rank := NewTerms(val.source).RankMatch(val.target); rank != val.rank {
+			t.Errorf("expected ranking %d, got %d for %s in %s",
+				val.rank, rank, val.source, val.target)
+		}
+	}
+
+	ranks := terms.RankFind([]string{"wheel", "cartwheel", "wheelbarrow cart"})
+	wanted := Ranks{{"wheel cart", "cartwheel", 0, 1}, {"wheel cart", "wheelbarrow cart", 7, 2}}
+	if !reflect.DeepEqual(ranks, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, ranks)
+	}
+}
+
+func TestTermsMatchPositions(t *testing.T) {
+	terms := NewTermsFold("q3 REPORT")
+	wanted := [][]int{{13, 14}, {6, 7, 8, 9, 10, 11}}
+	if positions := terms.MatchPositions("Sales Report Q3"); !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+
+	terms = NewTerms("ab a")
+	terms.Disjoint = true
+	wanted = [][]int{{0, 2}, {1}}
+	if positions := terms.MatchPositions("aab"); !reflect.DeepEqual(positions, wanted) {
+		t.Errorf("expected positions %v, got %v", wanted, positions)
+	}
+}
+
+func ExampleNewTermsFold() {
+	terms := NewTermsFold("report q3 sales")
+	fmt.Println(terms.Match("Sales Report Q3"))
+	fmt.Println(terms.MatchPositions("Sales Report Q3"))
+	// Output:
+	// true
+	// [[6 7 8 9 10 11] [13 14] [0 1 2 3 4]]
+}
@@ -0,0 +1,183 @@
+package fuzzy
+
+import (
+	"sort"
+
+	"golang.org/x/text/transform"
+)
+
+// A Trie holds weighted words for autocompletion. Unlike Match, which
+// requires every rune of the input, and LevenshteinDistance, which compares
+// whole words, Trie.Complete finds the words starting with a prefix within a
+// few edits of the input, so that both "cartwh" and "catrwh" complete to
+// "cartwheel".
+//
+// A Trie is safe for concurrent lookups, but Add must not be called
+// concurrently with any other method.
+type Trie struct {
+	root        trieNode
+	size        int
+	transformer func() transform.Transformer
+}
+
+type trieNode struct {
+	edges   []trieEdge  // sorted by rune
+	entries []trieEntry // words ending at this node
+}
+
+type trieEdge struct {
+	r    rune
+	node *trieNode
+}
+
+type trieEntry struct {
+	word   string
+	weight float64
+}
+
+// A Completion is a word returned by Trie.Complete.
+type Completion struct {
+	// Word is the completed word.
+	Word string
+
+	// Distance is the lowest Levenshtein distance between the input and a
+	// prefix of Word.
+	Distance int
+
+	// Weight is the weight Word was added with.
+	Weight float64
+}
+
+// NewTrie returns an empty Trie.
+func NewTrie() *Trie {
+	return &Trie{transformer: noopTransformer}
+}
+
+// NewTrieFold returns an empty Trie that completes words case-insensitively.
+func NewTrieFold() *Trie {
+	return &Trie{transformer: foldTransformer}
+}
+
+// Add adds word to the trie with weight, which is used to rank completions
+// at the same distance, higher first. Adding a word again updates its weight.
+func (t *Trie) Add(word string, weight float64) {
+	n := &t.root
+	for _, r := range stringTransform(word, t.transformer()) {
+		n = n.child(r, true)
+	}
+
+	for i, e := range n.entries {
+		if e.word == word {
+			n.entries[i].weight = weight
+			return
+		}
+	}
+	n.entries = append(n.entries, trieEntry{word, weight})
+	t.size++
+}
+
+// child returns the child of n for r, adding it if create is true, or nil.
+func (n *trieNode) child(r rune, create bool) *trieNode {
+	i := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].r >= r
+	})
+	if i < len(n.edges) && n.edges[i].r == r {
+		return n.edges[i].node
+	}
+	if !create {
+		return nil
+	}
+
+	child := &trieNode{}
+	n.edges = append(n.edges, trieEdge{})
+	copy(n.edges[i+1:], n.edges[i:])
+	n.edges[i] = trieEdge{r, child}
+	return child
+}
+
+// Len returns the number of words in the trie.
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Complete returns the words that have a prefix within maxDistance edits of
+// input, sorted by distance, then by descending weight, then alphabetically.
+// If limit is positive, at most limit completions are returned.
+func (t *Trie) Complete(input string, maxDistance, limit int) []Completion {
+	source := []rune(stringTransform(input, t.transformer()))
+
+	// row[i] is the Levenshtein distance between source[:i] and the path to
+	// the current node.
+	row := make([]int, len(source)+1)
+	for i := range row {
+		row[i] = i
+	}
+
+	var completions []Completion
+	t.complete(&t.root, source, row, row[len(source)], maxDistance, &completions)
+
+	sort.Slice(completions, func(i, j int) bool {
+		a, b := completions[i], completions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.Word < b.Word
+	})
+
+	if limit > 0 && len(completions) > limit {
+		completions = completions[:limit]
+	}
+
+	return completions
+}
+
+// complete appends the completions below n, where best is the lowest
+// distance between source and a prefix of the path to n.
+func (t *Trie) complete(n *trieNode, source []rune, row []int, best, maxDistance int, completions *[]Completion) {
+	if best <= maxDistance {
+		for _, e := range n.entries {
+			*completions = append(*completions, Completion{e.word, best, e.weight})
+		}
+	}
+
+	lowest := row[0]
+	for _, d := range row {
+		lowest = min2(lowest, d)
+	}
+	if lowest > maxDistance {
+		// The distance can't decrease further down, so either all or none of
+		// the words below n are completions.
+		if best <= maxDistance {
+			for _, e := range n.edges {
+				e.node.collect(best, completions)
+			}
+		}
+		return
+	}
+
+	for _, e := range n.edges {
+		next := make([]int, len(row))
+		next[0] = row[0] + 1
+		for i := 1; i < len(row); i++ {
+			cost := 0
+			if source[i-1] != e.r {
+				cost = 1
+			}
+			next[i] = min(row[i]+1, next[i-1]+1, row[i-1]+cost)
+		}
+		t.complete(e.node, source, next, min2(best, next[len(source)]), maxDistance, completions)
+	}
+}
+
+// collect appends all words below n as completions at distance.
+func (n *trieNode) collect(distance int, completions *[]Completion) {
+	for _, e := range n.entries {
+		*completions = append(*completions, Completion{e.word, distance, e.weight})
+	}
+	for _, e := range n.edges {
+		e.node.collect(distance, completions)
+	}
+}
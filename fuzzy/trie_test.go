@@ -0,0 +1,107 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTrieComplete(t *testing.T) {
+	trie := NewTrie()
+	for word, weight := range map[string]float64{
+		"cartwheel": 5, "cart": 10, "carton": 2, "cat": 20, "catalog": 1, "wheel": 3,
+	} {
+		trie.Add(word, weight)
+	}
+
+	var completeTests = []struct {
+		input       string
+		maxDistance int
+		limit       int
+		wanted      []string
+	}{
+		{"cartwh", 0, 0, []string{"cartwheel"}},
+		{"catrwh", 2, 0, []string{"cartwheel"}},
+		{"cart", 0, 0, []string{"cart", "cartwheel", "carton"}},
+		{"cat", 0, 0, []string{"cat", "catalog"}},
+		{"cat", 1, 0, []string{"cat", "catalog", "cart", "cartwheel", "carton"}},
+		{"cat", 1, 2, []string{"cat", "catalog"}},
+		{"whel", 1, 0, []string{"wheel"}},
+		{"xyz", 2, 0, nil},
+		{"", 0, 0, []string{"cat", "cart", "cartwheel", "wheel", "carton", "catalog"}},
+	}
+
+	for _, test := range completeTests {
+		var words []string
+		for _, c := range trie.Complete(test.input, test.maxDistance, test.limit) {
+			words = append(words, c.Word)
+		}
+		if !reflect.DeepEqual(words, test.wanted) {
+			t.Errorf("got %q, expected %q for %s within %d", words, test.wanted, test.input, test.maxDistance)
+		}
+	}
+}
+
+func TestTrieAdd(t *testing.T) {
+	trie := NewTrieFold()
+	trie.Add("Go", 1)
+	trie.Add("go", 2)
+	trie.Add("Go", 3)
+	if trie.Len() != 2 {
+		t.Errorf("expected 2 words, got %d", trie.Len())
+	}
+
+	wanted := []Completion{{"Go", 0, 3}, {"go", 0, 2}}
+	if got := trie.Complete("GO", 0, 0); !reflect.DeepEqual(got, wanted) {
+		t.Errorf("got %v, expected %v", got, wanted)
+	}
+}
+
+func TestTrieAgreesWithBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := randomWords(rng, 300)
+
+	trie := NewTrie()
+	for _, w := range words {
+		trie.Add(w, 0)
+	}
+
+	for _, input := range randomWords(rng, 50) {
+		for k := 0; k <= 3; k++ {
+			wanted := make(map[string]int)
+			for _, w := range words {
+				best := -1
+				for i := range w + " " {
+					if d := LevenshteinDistance(input, w[:i]); best < 0 || d < best {
+						best = d
+					}
+				}
+				if best <= k {
+					wanted[w] = best
+				}
+			}
+
+			got := make(map[string]int)
+			for _, c := range trie.Complete(input, k, 0) {
+				got[c.Word] = c.Distance
+			}
+			if !reflect.DeepEqual(got, wanted) {
+				t.Fatalf("Complete(%q, %d): got %v, expected %v", input, k, got, wanted)
+			}
+		}
+	}
+}
+
+func ExampleTrie_Complete() {
+	trie := NewTrie()
+	trie.Add("cartwheel", 1)
+	trie.Add("cartography", 2)
+	trie.Add("wheel", 3)
+
+	for _, c := range trie.Complete("catrwh", 2, 0) {
+		fmt.Println(c.Word, c.Distance)
+	}
+	// Output:
+	// cartwheel 2
+}